@@ -0,0 +1,113 @@
+package errors
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/jpillora/backoff"
+)
+
+// BackoffStrategy determines how long to wait between attempts.
+// Implementations must be safe for concurrent use as a strategy may be shared
+// by many concurrent retry loops.
+type BackoffStrategy interface {
+	// Backoff returns the delay before the next attempt. retry is the number
+	// of retries made so far (0 for the wait before the first retry) and
+	// prev the previous delay, 0 before the first retry.
+	Backoff(retry int, prev time.Duration) time.Duration
+}
+
+// ConstantBackoff waits Delay between all attempts.
+type ConstantBackoff struct {
+	Delay time.Duration
+}
+
+func (b ConstantBackoff) Backoff(retry int, prev time.Duration) time.Duration {
+	return b.Delay
+}
+
+// LinearBackoff waits Min before the first retry, increasing the delay by Step
+// for every retry thereafter up to Max. A zero Max means no maximum.
+type LinearBackoff struct {
+	Min, Step, Max time.Duration
+}
+
+func (b LinearBackoff) Backoff(retry int, prev time.Duration) time.Duration {
+	d := b.Min + time.Duration(retry)*b.Step
+	if d < b.Min {
+		// overflowed
+		d = math.MaxInt64
+	}
+	if b.Max > 0 && d > b.Max {
+		return b.Max
+	}
+	return d
+}
+
+// ExponentialBackoff waits Min before the first retry, multiplying the delay
+// by Factor for every retry thereafter up to Max. With Jitter, each delay is
+// instead picked at random between Min and the exponential delay.
+// Zero values default to a Min of 100ms, a Max of 10s and a Factor of 2.
+// This is the strategy used by default (see RetryWithMinBackoff et al).
+type ExponentialBackoff struct {
+	Min, Max time.Duration
+	Factor   float64
+	Jitter   bool
+}
+
+func (b ExponentialBackoff) Backoff(retry int, prev time.Duration) time.Duration {
+	jb := backoff.Backoff{Min: b.Min, Max: b.Max, Factor: b.Factor, Jitter: b.Jitter}
+	return jb.ForAttempt(float64(retry))
+}
+
+// DecorrelatedJitterBackoff waits Min before the first retry and thereafter a
+// random delay between Min and three times the previous delay, up to Max.
+// This is the "decorrelated jitter" strategy described in
+// https://aws.amazon.com/blogs/architecture/exponential-backoff-and-jitter/
+type DecorrelatedJitterBackoff struct {
+	Min, Max time.Duration
+}
+
+func (b DecorrelatedJitterBackoff) Backoff(retry int, prev time.Duration) time.Duration {
+	if prev < b.Min {
+		prev = b.Min
+	}
+	upper := 3 * prev
+	if upper < prev {
+		// overflowed
+		upper = math.MaxInt64
+	}
+	d := b.Min
+	if upper > b.Min {
+		d += time.Duration(rand.Int63n(int64(upper - b.Min)))
+	}
+	if b.Max > 0 && d > b.Max {
+		return b.Max
+	}
+	return d
+}
+
+// FibonacciBackoff waits Min multiplied by the Fibonacci sequence
+// (1, 1, 2, 3, 5, 8...) up to Max. A zero Max means no maximum.
+type FibonacciBackoff struct {
+	Min, Max time.Duration
+}
+
+func (b FibonacciBackoff) Backoff(retry int, prev time.Duration) time.Duration {
+	limit := time.Duration(math.MaxInt64)
+	if b.Max > 0 {
+		limit = b.Max
+	}
+	fib, next := time.Duration(1), time.Duration(1)
+	for i := 0; i < retry; i++ {
+		fib, next = next, fib+next
+		if b.Min > 0 && fib > limit/b.Min {
+			return limit
+		}
+	}
+	if d := b.Min * fib; d < limit {
+		return d
+	}
+	return limit
+}
@@ -0,0 +1,110 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tomogoma/go-typed-errors"
+	"github.com/tomogoma/go-typed-errors/errorstest"
+)
+
+func TestBackoffStrategies(t *testing.T) {
+	tt := []struct {
+		name      string
+		strategy  errors.BackoffStrategy
+		expDelays []time.Duration
+	}{
+		{
+			name:      "constant",
+			strategy:  errors.ConstantBackoff{Delay: time.Second},
+			expDelays: []time.Duration{time.Second, time.Second, time.Second, time.Second},
+		},
+		{
+			name:     "linear",
+			strategy: errors.LinearBackoff{Min: time.Second, Step: 2 * time.Second, Max: 6 * time.Second},
+			expDelays: []time.Duration{time.Second, 3 * time.Second, 5 * time.Second,
+				6 * time.Second},
+		},
+		{
+			name:     "exponential",
+			strategy: errors.ExponentialBackoff{Min: time.Second, Max: 10 * time.Second, Factor: 3},
+			expDelays: []time.Duration{time.Second, 3 * time.Second, 9 * time.Second,
+				10 * time.Second},
+		},
+		{
+			name:     "fibonacci",
+			strategy: errors.FibonacciBackoff{Min: time.Second, Max: 4 * time.Second},
+			expDelays: []time.Duration{time.Second, time.Second, 2 * time.Second,
+				3 * time.Second, 4 * time.Second},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var prev time.Duration
+			for retry, exp := range tc.expDelays {
+				prev = tc.strategy.Backoff(retry, prev)
+				if prev != exp {
+					t.Errorf("expected delay %v for retry %d, got %v", exp, retry, prev)
+				}
+			}
+		})
+	}
+}
+
+func TestFibonacciBackoff_noOverflow(t *testing.T) {
+	b := errors.FibonacciBackoff{Min: time.Hour}
+	if d := b.Backoff(500, 0); d <= 0 {
+		t.Errorf("expected a positive delay, got %v", d)
+	}
+}
+
+func TestDecorrelatedJitterBackoff(t *testing.T) {
+	b := errors.DecorrelatedJitterBackoff{Min: time.Second, Max: 20 * time.Second}
+	var prev time.Duration
+	for retry := 0; retry < 100; retry++ {
+		d := b.Backoff(retry, prev)
+		upper := 3 * prev
+		if upper < time.Second {
+			upper = 3 * time.Second
+		}
+		if upper > b.Max {
+			upper = b.Max
+		}
+		if d < b.Min || d > upper {
+			t.Fatalf("expected delay within [%v, %v] for retry %d, got %v", b.Min, upper, retry, d)
+		}
+		prev = d
+	}
+}
+
+func TestDoWithRetries_backoffStrategy(t *testing.T) {
+	clock := errorstest.NewFakeClock(time.Now())
+	errors.DoWithRetries(func() error {
+		return errors.NewRetryable("unavailable")
+	},
+		errors.RetryWithClock(clock),
+		errors.RetryWithMinBackoff(time.Hour), // has no effect on the strategy
+		errors.RetryWithBackoffStrategy(errors.LinearBackoff{Min: time.Second, Step: time.Second}),
+	)
+	expSleeps := []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 4 * time.Second}
+	if fmt.Sprint(clock.Sleeps()) != fmt.Sprint(expSleeps) {
+		t.Errorf("expected sleeps %v, got %v", expSleeps, clock.Sleeps())
+	}
+}
+
+func TestDoWithRetries_exponentialOptions(t *testing.T) {
+	clock := errorstest.NewFakeClock(time.Now())
+	errors.DoWithRetries(func() error {
+		return errors.NewRetryable("unavailable")
+	},
+		errors.RetryWithClock(clock),
+		errors.RetryWithMinBackoff(time.Second),
+		errors.RetryWithMaxBackoff(5*time.Second),
+		errors.RetryWithBackoffFactor(3),
+	)
+	expSleeps := []time.Duration{time.Second, 3 * time.Second, 5 * time.Second, 5 * time.Second}
+	if fmt.Sprint(clock.Sleeps()) != fmt.Sprint(expSleeps) {
+		t.Errorf("expected sleeps %v, got %v", expSleeps, clock.Sleeps())
+	}
+}
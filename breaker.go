@@ -0,0 +1,226 @@
+package errors
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is wrapped by the error a CircuitBreaker returns instead of
+// making a call while open.
+var ErrCircuitOpen = goerrors.New("circuit breaker is open")
+
+// BreakerState is the state of a CircuitBreaker.
+type BreakerState int
+
+const (
+	// BreakerClosed lets all calls through.
+	BreakerClosed BreakerState = iota
+	// BreakerOpen fails all calls fast until the cooldown elapses.
+	BreakerOpen
+	// BreakerHalfOpen lets a single probe call through, closing the breaker if
+	// it succeeds and opening it again if it fails.
+	BreakerHalfOpen
+)
+
+func (s BreakerState) String() string {
+	switch s {
+	case BreakerClosed:
+		return "closed"
+	case BreakerOpen:
+		return "open"
+	case BreakerHalfOpen:
+		return "half_open"
+	default:
+		return fmt.Sprintf("BreakerState(%d)", int(s))
+	}
+}
+
+// CircuitBreaker stops calls to a failing dependency. It counts consecutive
+// failures, i.e. errors deemed retryable by its IsRetryableErrChecker; other
+// errors show the dependency is up and count as successes. Once the failure
+// threshold is reached the breaker opens and calls fail fast with a Retryable
+// error that wraps ErrCircuitOpen and has its RetryAfter set to the time left
+// until the cooldown elapses. The breaker then half-opens to let a single probe
+// call through.
+//
+// Since the error returned while open is retryable and says when to retry,
+// a CircuitBreaker composes with DoWithRetries e.g:
+//
+//	cb := typederrs.NewCircuitBreaker()
+//	err := typederrs.DoWithRetries(func() error {
+//	    return cb.Do(callDependency)
+//	})
+//
+// A CircuitBreaker is safe for concurrent use and should be shared by all
+// calls to the same dependency.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	checker   IsRetryableErrChecker
+	clock     Clock
+
+	mu       sync.Mutex
+	state    BreakerState
+	failures int
+	openedAt time.Time
+	probing  bool
+	// generation is incremented on every change of state such that the
+	// outcomes of calls admitted in an earlier state can be ignored.
+	generation uint64
+}
+
+type BreakerOption func(*CircuitBreaker)
+
+// BreakerWithFailureThreshold sets the number of consecutive failures that
+// opens the breaker, 5 by default.
+func BreakerWithFailureThreshold(n int) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.threshold = n
+	}
+}
+
+// BreakerWithCooldown sets how long the breaker stays open before letting a
+// probe call through, 30s by default.
+func BreakerWithCooldown(d time.Duration) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.cooldown = d
+	}
+}
+
+// BreakerWithRetryableErrChecker sets the checker that decides which errors
+// count as failures, RetryableErrCheck by default.
+func BreakerWithRetryableErrChecker(ch IsRetryableErrChecker) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.checker = ch
+	}
+}
+
+// BreakerWithClock sets the Clock used to time the cooldown, SystemClock by
+// default.
+func BreakerWithClock(c Clock) BreakerOption {
+	return func(b *CircuitBreaker) {
+		b.clock = c
+	}
+}
+
+// NewCircuitBreaker creates a new closed CircuitBreaker.
+func NewCircuitBreaker(opts ...BreakerOption) *CircuitBreaker {
+	b := &CircuitBreaker{
+		threshold: 5,
+		cooldown:  30 * time.Second,
+		checker:   &RetryableErrCheck{},
+		clock:     SystemClock,
+	}
+	for _, f := range opts {
+		f(b)
+	}
+	return b
+}
+
+// State returns the current state of the breaker.
+func (b *CircuitBreaker) State() BreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && b.cooldownLeft() <= 0 {
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// Do calls f if the breaker allows it, returning the error f returns.
+// Otherwise f is not called and a Retryable error wrapping ErrCircuitOpen is
+// returned.
+func (b *CircuitBreaker) Do(f func() error) error {
+	return b.DoCtx(context.Background(), func(context.Context) error {
+		return f()
+	})
+}
+
+// DoCtx is like Do but passes ctx on to f.
+func (b *CircuitBreaker) DoCtx(ctx context.Context, f func(ctx context.Context) error) error {
+	gen, err := b.allow()
+	if err != nil {
+		return err
+	}
+	panicked := true
+	defer func() {
+		if panicked {
+			b.record(gen, true)
+		}
+	}()
+	err = f(ctx)
+	panicked = false
+	b.record(gen, err != nil && b.checker.IsRetryableError(err))
+	return err
+}
+
+// allow returns the generation of the state a call is admitted in, or the
+// error to fail fast with if the call may not be made.
+func (b *CircuitBreaker) allow() (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case BreakerOpen:
+		left := b.cooldownLeft()
+		if left > 0 {
+			return 0, WrapRetryableAfter(ErrCircuitOpen, left, nil)
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+	case BreakerHalfOpen:
+		if b.probing {
+			return 0, WrapRetryable(ErrCircuitOpen, nil)
+		}
+		b.probing = true
+	}
+	return b.generation, nil
+}
+
+// record updates the state of the breaker with the outcome of a call admitted
+// in generation gen. Outcomes of calls admitted before the last change of
+// state are ignored e.g. a slow call admitted while closed that completes
+// during the probe.
+func (b *CircuitBreaker) record(gen uint64, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if gen != b.generation {
+		return
+	}
+	switch b.state {
+	case BreakerHalfOpen:
+		b.probing = false
+		if failed {
+			b.open()
+			return
+		}
+		b.setState(BreakerClosed)
+		b.failures = 0
+	case BreakerClosed:
+		if !failed {
+			b.failures = 0
+			return
+		}
+		b.failures++
+		if b.failures >= b.threshold {
+			b.open()
+		}
+	}
+}
+
+func (b *CircuitBreaker) open() {
+	b.setState(BreakerOpen)
+	b.openedAt = b.clock.Now()
+	b.failures = 0
+}
+
+func (b *CircuitBreaker) setState(s BreakerState) {
+	b.state = s
+	b.generation++
+}
+
+func (b *CircuitBreaker) cooldownLeft() time.Duration {
+	return b.cooldown - b.clock.Now().Sub(b.openedAt)
+}
@@ -0,0 +1,184 @@
+package errors_test
+
+import (
+	goerrors "errors"
+	"testing"
+	"time"
+
+	"github.com/tomogoma/go-typed-errors"
+	"github.com/tomogoma/go-typed-errors/errorstest"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := errorstest.NewFakeClock(time.Now())
+	cb := errors.NewCircuitBreaker(
+		errors.BreakerWithFailureThreshold(2),
+		errors.BreakerWithCooldown(10*time.Second),
+		errors.BreakerWithClock(clock),
+	)
+	calls := 0
+	call := func(err error) error {
+		return cb.Do(func() error {
+			calls++
+			return err
+		})
+	}
+	unavailable := errors.NewRetryable("unavailable")
+
+	// Errors that are not retryable show the dependency is up.
+	call(unavailable)
+	call(errors.NewNotFound("no such user"))
+	call(unavailable)
+	if s := cb.State(); s != errors.BreakerClosed {
+		t.Fatalf("expected state %v, got %v", errors.BreakerClosed, s)
+	}
+
+	call(unavailable)
+	if s := cb.State(); s != errors.BreakerOpen {
+		t.Fatalf("expected state %v, got %v", errors.BreakerOpen, s)
+	}
+	clock.Advance(4 * time.Second)
+	err := call(nil)
+	if calls != 4 {
+		t.Errorf("expected no call while open, got %d calls", calls)
+	}
+	if !goerrors.Is(err, errors.ErrCircuitOpen) || !goerrors.Is(err, errors.ErrRetryable) {
+		t.Errorf("expected a retryable error matching ErrCircuitOpen, got %v", err)
+	}
+	if after, ok := errors.RetryAfter(err); !ok || after != 6*time.Second {
+		t.Errorf("expected retry after 6s, got %v, %t", after, ok)
+	}
+
+	// A failed probe opens the breaker again.
+	clock.Advance(6 * time.Second)
+	if s := cb.State(); s != errors.BreakerHalfOpen {
+		t.Fatalf("expected state %v, got %v", errors.BreakerHalfOpen, s)
+	}
+	call(unavailable)
+	if calls != 5 {
+		t.Errorf("expected a probe call, got %d calls", calls)
+	}
+	if s := cb.State(); s != errors.BreakerOpen {
+		t.Fatalf("expected state %v, got %v", errors.BreakerOpen, s)
+	}
+
+	// A successful probe closes it.
+	clock.Advance(10 * time.Second)
+	if err := call(nil); err != nil {
+		t.Errorf("expected probe to succeed, got %v", err)
+	}
+	if s := cb.State(); s != errors.BreakerClosed {
+		t.Fatalf("expected state %v, got %v", errors.BreakerClosed, s)
+	}
+}
+
+func TestCircuitBreaker_halfOpenSingleProbe(t *testing.T) {
+	clock := errorstest.NewFakeClock(time.Now())
+	cb := errors.NewCircuitBreaker(
+		errors.BreakerWithFailureThreshold(1),
+		errors.BreakerWithCooldown(time.Second),
+		errors.BreakerWithClock(clock),
+	)
+	cb.Do(func() error { return errors.NewRetryable("unavailable") })
+	clock.Advance(time.Second)
+
+	var probeErr error
+	err := cb.Do(func() error {
+		probeErr = cb.Do(func() error { return nil })
+		return nil
+	})
+	if err != nil {
+		t.Errorf("expected probe to succeed, got %v", err)
+	}
+	if !goerrors.Is(probeErr, errors.ErrCircuitOpen) {
+		t.Errorf("expected call during probe to fail with ErrCircuitOpen, got %v", probeErr)
+	}
+}
+
+func TestCircuitBreaker_withRetries(t *testing.T) {
+	clock := errorstest.NewFakeClock(time.Now())
+	cb := errors.NewCircuitBreaker(
+		errors.BreakerWithFailureThreshold(2),
+		errors.BreakerWithCooldown(time.Minute),
+		errors.BreakerWithClock(clock),
+	)
+	calls := 0
+	err := errors.DoWithRetries(func() error {
+		return cb.Do(func() error {
+			calls++
+			if calls <= 2 {
+				return errors.NewRetryable("unavailable")
+			}
+			return nil
+		})
+	},
+		errors.RetryWithClock(clock),
+		errors.RetryWithBackoffStrategy(errors.ConstantBackoff{Delay: time.Second}),
+	)
+	if err != nil {
+		t.Fatalf("expected success after the cooldown, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 calls, got %d", calls)
+	}
+	// The backoff after each failure, then the rest of the cooldown.
+	expSleeps := []time.Duration{time.Second, time.Second, time.Minute - time.Second}
+	sleeps := clock.Sleeps()
+	if len(sleeps) != len(expSleeps) {
+		t.Fatalf("expected sleeps %v, got %v", expSleeps, sleeps)
+	}
+	for i := range sleeps {
+		if sleeps[i] != expSleeps[i] {
+			t.Errorf("expected sleeps %v, got %v", expSleeps, sleeps)
+			break
+		}
+	}
+}
+
+func TestCircuitBreaker_staleOutcome(t *testing.T) {
+	clock := errorstest.NewFakeClock(time.Now())
+	cb := errors.NewCircuitBreaker(
+		errors.BreakerWithFailureThreshold(1),
+		errors.BreakerWithCooldown(time.Second),
+		errors.BreakerWithClock(clock),
+	)
+
+	// A slow call admitted while closed...
+	slowStarted, slowRelease := make(chan struct{}), make(chan struct{})
+	slowDone := make(chan error)
+	go func() {
+		slowDone <- cb.Do(func() error {
+			close(slowStarted)
+			<-slowRelease
+			return nil
+		})
+	}()
+	<-slowStarted
+
+	cb.Do(func() error { return errors.NewRetryable("unavailable") })
+	clock.Advance(time.Second)
+
+	// ...succeeds while the probe is in flight.
+	var stateDuringProbe errors.BreakerState
+	var duringProbeErr error
+	cb.Do(func() error {
+		close(slowRelease)
+		<-slowDone
+		stateDuringProbe = cb.State()
+		duringProbeErr = cb.Do(func() error {
+			t.Errorf("expected no call during the probe")
+			return nil
+		})
+		return errors.NewRetryable("still unavailable")
+	})
+
+	if stateDuringProbe != errors.BreakerHalfOpen {
+		t.Errorf("expected state %v during the probe, got %v", errors.BreakerHalfOpen, stateDuringProbe)
+	}
+	if !goerrors.Is(duringProbeErr, errors.ErrCircuitOpen) {
+		t.Errorf("expected call during the probe to fail with ErrCircuitOpen, got %v", duringProbeErr)
+	}
+	if s := cb.State(); s != errors.BreakerOpen {
+		t.Errorf("expected state %v after the failed probe, got %v", errors.BreakerOpen, s)
+	}
+}
@@ -0,0 +1,85 @@
+package errors
+
+import (
+	goerrors "errors"
+	"sync"
+)
+
+// ErrRetryBudgetExhausted is the Reason of a RetriesExhaustedError returned
+// because the RetryBudget set using RetryWithBudget allowed no more retries.
+var ErrRetryBudgetExhausted = goerrors.New("retry budget exhausted")
+
+// RetryBudget limits retries to a ratio of requests, keeping the callers of a
+// struggling dependency from multiplying the load on it by retrying. It is a
+// token bucket: every request (first attempt) deposits Ratio tokens and every
+// retry withdraws one, so that over time retries make up at most Ratio of
+// requests. The bucket holds at most MaxTokens, which is also the number of
+// tokens it starts with, allowing that many retries in a burst.
+//
+// A RetryBudget is safe for concurrent use and is meant to be shared by all
+// calls to the same dependency (see RetryWithBudget).
+type RetryBudget struct {
+	ratio     float64
+	maxTokens float64
+
+	mu     sync.Mutex
+	tokens float64
+}
+
+type RetryBudgetOption func(*RetryBudget)
+
+// RetryBudgetWithRatio sets the ratio of retries to requests allowed, 0.1
+// (i.e. 10%) by default.
+func RetryBudgetWithRatio(r float64) RetryBudgetOption {
+	return func(b *RetryBudget) {
+		b.ratio = r
+	}
+}
+
+// RetryBudgetWithMaxTokens sets the maximum number of tokens the budget holds,
+// 10 by default.
+func RetryBudgetWithMaxTokens(n float64) RetryBudgetOption {
+	return func(b *RetryBudget) {
+		b.maxTokens = n
+	}
+}
+
+// NewRetryBudget creates a new RetryBudget holding its maximum number of
+// tokens.
+func NewRetryBudget(opts ...RetryBudgetOption) *RetryBudget {
+	b := &RetryBudget{ratio: 0.1, maxTokens: 10}
+	for _, f := range opts {
+		f(b)
+	}
+	b.tokens = b.maxTokens
+	return b
+}
+
+// Deposit records a request, adding Ratio tokens to the budget.
+func (b *RetryBudget) Deposit() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tokens += b.ratio
+	if b.tokens > b.maxTokens {
+		b.tokens = b.maxTokens
+	}
+}
+
+// Withdraw takes a token for a retry, returning false, and taking nothing, if
+// the budget has less than one token left.
+func (b *RetryBudget) Withdraw() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// Tokens returns the number of tokens left in the budget.
+func (b *RetryBudget) Tokens() float64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
@@ -0,0 +1,66 @@
+package errors_test
+
+import (
+	goerrors "errors"
+	"sync"
+	"testing"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func TestRetryBudget(t *testing.T) {
+	b := errors.NewRetryBudget(errors.RetryBudgetWithRatio(0.5), errors.RetryBudgetWithMaxTokens(2))
+	if !b.Withdraw() || !b.Withdraw() {
+		t.Fatalf("expected a full budget to allow 2 retries")
+	}
+	if b.Withdraw() {
+		t.Errorf("expected an empty budget to allow no retries")
+	}
+	b.Deposit()
+	if b.Withdraw() {
+		t.Errorf("expected half a token to allow no retries")
+	}
+	b.Deposit()
+	if !b.Withdraw() {
+		t.Errorf("expected 2 requests to allow a retry")
+	}
+	for i := 0; i < 10; i++ {
+		b.Deposit()
+	}
+	if tokens := b.Tokens(); tokens != 2 {
+		t.Errorf("expected tokens capped at 2, got %v", tokens)
+	}
+}
+
+func TestDoWithRetries_budget(t *testing.T) {
+	b := errors.NewRetryBudget(errors.RetryBudgetWithRatio(0.1), errors.RetryBudgetWithMaxTokens(3))
+	opts := append([]errors.RetryOption{errors.RetryWithBudget(b)}, fastRetries...)
+
+	var mu sync.Mutex
+	attempts := 0
+	errs := make([]error, 4)
+	var wg sync.WaitGroup
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = errors.DoWithRetries(func() error {
+				mu.Lock()
+				attempts++
+				mu.Unlock()
+				return errors.NewRetryable("unavailable")
+			}, opts...)
+		}(i)
+	}
+	wg.Wait()
+
+	// 4 first attempts and the 3 retries the full budget allows.
+	if attempts != 7 {
+		t.Errorf("expected 7 attempts, got %d", attempts)
+	}
+	for _, err := range errs {
+		if !goerrors.Is(err, errors.ErrRetryBudgetExhausted) {
+			t.Errorf("expected error matching ErrRetryBudgetExhausted, got %v", err)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"math"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxErrBodySize is the maximum number of bytes of a response body
+// FromHTTPResponse reads to restore the error message.
+const maxErrBodySize = 64 << 10
+
+// FromHTTPResponse returns the Error matching an error response (status code
+// 400 or above) received from a server, false if resp is not an error response.
+//
+// The Kind of the Error is that named in the body if it is a JSONBody, Problem
+// (with a type prefixed by ProblemTypeBase) or XMLError naming a known Kind
+// whose HTTP status code is that of resp. Otherwise the Kind is derived from
+// the status code:
+//
+//	400:                KindClient
+//	401:                KindUnauthorized
+//	403:                KindForbidden
+//	404:                KindNotFound
+//	409:                KindConflict
+//	412:                KindPreconditionFailed
+//	501:                KindNotImplemented
+//	429, 502, 503, 504: KindRetryable
+//	other:              the registered Kind (see RegisterKind) with the status
+//	                    code if any, else KindClient for other 4xx and
+//	                    KindUnknown for other 5xx status codes.
+//
+// The message of the Error (its Data) is restored from the body, whether plain
+// text or any of the formats written by this package. HttpMsg is left empty as
+// the body of another server is not necessarily fit for our own clients; set it
+// to e.Error() to pass the message on.
+// The Error's RetryAfter is set from the Retry-After header if present.
+// resp.Body is left readable from the start.
+func FromHTTPResponse(resp *http.Response) (Error, bool) {
+
+	if resp.StatusCode < 400 {
+		return Error{}, false
+	}
+
+	k, msg := KindUnknown, ""
+	if resp.Body != nil {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrBodySize))
+		resp.Body = struct {
+			io.Reader
+			io.Closer
+		}{Reader: io.MultiReader(bytes.NewReader(b), resp.Body), Closer: resp.Body}
+		k, msg = parseErrBody(resp.Header.Get("Content-Type"), b)
+	}
+
+	if k == KindUnknown || k.HTTPStatus() != resp.StatusCode {
+		// The status code is authoritative e.g. a server fault must not
+		// pass for a client error because the body says so.
+		k = kindForHTTPStatus(resp.StatusCode)
+	}
+	e := newError(k, nil, "", msg)
+	if msg == "" {
+		e.Data = resp.Status
+	}
+	e.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	return e, true
+}
+
+// parseRetryAfter returns the delay specified by a Retry-After header value,
+// which is either a number of seconds or an HTTP date, relative to now.
+// Returns 0 if the value is empty, invalid or in the past.
+func parseRetryAfter(v string, now time.Time) time.Duration {
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.ParseInt(v, 10, 64); err == nil {
+		if secs <= 0 {
+			return 0
+		}
+		if secs > int64(math.MaxInt64/time.Second) {
+			return math.MaxInt64
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// parseErrBody returns the Kind and message contained in an error response
+// body of the given Content-Type. The Kind is KindUnknown if not contained
+// in the body or not known.
+func parseErrBody(contentType string, b []byte) (Kind, string) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/problem+json":
+		var p Problem
+		if err := json.Unmarshal(b, &p); err == nil {
+			k := KindUnknown
+			if name := strings.TrimPrefix(p.Type, ProblemTypeBase); name != p.Type {
+				k, _ = KindByName(name)
+			}
+			if p.Detail != "" {
+				return k, p.Detail
+			}
+			return k, p.Title
+		}
+	case "application/json":
+		var body JSONBody
+		if err := json.Unmarshal(b, &body); err == nil && body.Error.Message != "" {
+			k, _ := KindByName(body.Error.Class)
+			return k, body.Error.Message
+		}
+	case "application/xml", "text/xml":
+		var body XMLError
+		if err := xml.Unmarshal(b, &body); err == nil && body.Message != "" {
+			k, _ := KindByName(body.Class)
+			return k, body.Message
+		}
+	}
+	return KindUnknown, strings.TrimSpace(string(b))
+}
+
+// kindForHTTPStatus returns the Kind matching an error response's status code
+// as documented on FromHTTPResponse.
+func kindForHTTPStatus(status int) Kind {
+	switch status {
+	case http.StatusBadRequest:
+		return KindClient
+	case http.StatusUnauthorized:
+		return KindUnauthorized
+	case http.StatusForbidden:
+		return KindForbidden
+	case http.StatusNotFound:
+		return KindNotFound
+	case http.StatusConflict:
+		return KindConflict
+	case http.StatusPreconditionFailed:
+		return KindPreconditionFailed
+	case http.StatusNotImplemented:
+		return KindNotImplemented
+	case http.StatusTooManyRequests, http.StatusBadGateway,
+		http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return KindRetryable
+	}
+	if k, ok := kindByHTTPStatus(status); ok {
+		return k
+	}
+	if status < 500 {
+		return KindClient
+	}
+	return KindUnknown
+}
@@ -0,0 +1,204 @@
+package errors_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func TestFromHTTPResponse_status(t *testing.T) {
+	tt := []struct {
+		status  int
+		expKind errors.Kind
+	}{
+		{status: http.StatusBadRequest, expKind: errors.KindClient},
+		{status: http.StatusUnauthorized, expKind: errors.KindUnauthorized},
+		{status: http.StatusForbidden, expKind: errors.KindForbidden},
+		{status: http.StatusNotFound, expKind: errors.KindNotFound},
+		{status: http.StatusConflict, expKind: errors.KindConflict},
+		{status: http.StatusPreconditionFailed, expKind: errors.KindPreconditionFailed},
+		{status: http.StatusTooManyRequests, expKind: errors.KindRetryable},
+		{status: http.StatusNotImplemented, expKind: errors.KindNotImplemented},
+		{status: http.StatusBadGateway, expKind: errors.KindRetryable},
+		{status: http.StatusServiceUnavailable, expKind: errors.KindRetryable},
+		{status: http.StatusGatewayTimeout, expKind: errors.KindRetryable},
+		{status: http.StatusLocked, expKind: kindLocked},
+		{status: http.StatusTeapot, expKind: errors.KindClient},
+		{status: http.StatusInternalServerError, expKind: errors.KindUnknown},
+	}
+	for _, tc := range tt {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: tc.status,
+				Status:     http.StatusText(tc.status),
+				Header:     http.Header{},
+				Body:       io.NopCloser(strings.NewReader("something went wrong\n")),
+			}
+			err, ok := errors.FromHTTPResponse(resp)
+			if !ok {
+				t.Fatalf("expected an error for status %d", tc.status)
+			}
+			if err.Kind() != tc.expKind {
+				t.Errorf("expected Kind %v, got %v", tc.expKind, err.Kind())
+			}
+			if err.Error() != "something went wrong" || err.HttpMsg != "" {
+				t.Errorf("expected message 'something went wrong', got '%s' (HttpMsg '%s')",
+					err.Error(), err.HttpMsg)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			if string(body) != "something went wrong\n" {
+				t.Errorf("expected the body to remain readable, got '%s'", body)
+			}
+		})
+	}
+}
+
+func TestFromHTTPResponse_notError(t *testing.T) {
+	for _, status := range []int{http.StatusOK, http.StatusNoContent, http.StatusFound} {
+		resp := &http.Response{StatusCode: status, Header: http.Header{}}
+		if err, ok := errors.FromHTTPResponse(resp); ok {
+			t.Errorf("expected no error for status %d, got %v", status, err)
+		}
+	}
+}
+
+func TestFromHTTPResponse_emptyBody(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusNotFound,
+		Status:     "404 Not Found",
+		Header:     http.Header{},
+		Body:       http.NoBody,
+	}
+	err, _ := errors.FromHTTPResponse(resp)
+	if !err.NotFound() || err.Error() != "404 Not Found" || err.HttpMsg != "" {
+		t.Errorf("expected a not found error with the status as message, got %+v", err)
+	}
+}
+
+func TestFromHTTPResponse_roundTrip(t *testing.T) {
+	encoders := []struct {
+		name string
+		enc  errors.HTTPEncoder
+	}{
+		{name: "text", enc: errors.EncodeText},
+		{name: "json", enc: errors.EncodeJSON},
+		{name: "problem", enc: errors.EncodeProblem},
+		{name: "xml", enc: errors.EncodeXML},
+	}
+	sent := []errors.Error{
+		errors.NewNotFoundWithHttp("user not found", "no rows"),
+		errors.NewConflictWithHttp("email taken", "unique violation"),
+		errors.NewRetryableWithHttp("try again later", "pool exhausted"),
+		kindGone.NewWithHttp("order archived", "archived"),
+		kindQuotaExceeded.NewWithHttp("quota exceeded", "quota"),
+	}
+	for _, e := range encoders {
+		for _, sentErr := range sent {
+			t.Run(e.name+"-"+sentErr.Kind().String(), func(t *testing.T) {
+				srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					sentErr.ToHTTPResponseWith(w, e.enc)
+				}))
+				defer srv.Close()
+				resp, err := http.Get(srv.URL)
+				if err != nil {
+					t.Fatalf("get: %v", err)
+				}
+				defer resp.Body.Close()
+				got, ok := errors.FromHTTPResponse(resp)
+				if !ok {
+					t.Fatalf("expected an error for status %d", resp.StatusCode)
+				}
+				if got.Error() != sentErr.HttpMsg || got.HttpMsg != "" {
+					t.Errorf("expected message '%s', got '%s' (HttpMsg '%s')",
+						sentErr.HttpMsg, got.Error(), got.HttpMsg)
+				}
+				expKind := sentErr.Kind()
+				if e.name == "text" {
+					// plain text bodies carry no Kind, only the status code.
+					expKind = map[errors.Kind]errors.Kind{
+						kindGone:          errors.KindNotFound,
+						kindQuotaExceeded: errors.KindRetryable,
+					}[expKind]
+					if expKind == errors.KindUnknown {
+						expKind = sentErr.Kind()
+					}
+				}
+				if got.Kind() != expKind {
+					t.Errorf("expected Kind %v, got %v", expKind, got.Kind())
+				}
+			})
+		}
+	}
+}
+
+func TestFromHTTPResponse_bodyKindStatusMismatch(t *testing.T) {
+	tt := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{name: "json", contentType: "application/json",
+			body: `{"error":{"class":"client","code":1,"message":"bad input"}}`},
+		{name: "problem", contentType: "application/problem+json",
+			body: `{"type":"urn:go-typed-errors:problem:client","detail":"bad input"}`},
+		{name: "xml", contentType: "application/xml",
+			body: `<error><class>client</class><code>1</code><message>bad input</message></error>`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusInternalServerError,
+				Status:     "500 Internal Server Error",
+				Header:     http.Header{"Content-Type": {tc.contentType}},
+				Body:       io.NopCloser(strings.NewReader(tc.body)),
+			}
+			got, ok := errors.FromHTTPResponse(resp)
+			if !ok {
+				t.Fatalf("expected an error")
+			}
+			if got.Kind() != errors.KindUnknown {
+				t.Errorf("expected Kind %v from the status code, got %v", errors.KindUnknown, got.Kind())
+			}
+			if got.Error() != "bad input" {
+				t.Errorf("expected message 'bad input', got '%s'", got.Error())
+			}
+		})
+	}
+}
+
+func TestFromHTTPResponse_retryAfter(t *testing.T) {
+	tt := []struct {
+		name       string
+		retryAfter string
+		expMin     time.Duration
+		expMax     time.Duration
+	}{
+		{name: "none", expMin: 0, expMax: 0},
+		{name: "seconds", retryAfter: "30", expMin: 30 * time.Second, expMax: 30 * time.Second},
+		{name: "http-date", retryAfter: time.Now().Add(time.Minute).UTC().Format(http.TimeFormat),
+			expMin: 58 * time.Second, expMax: time.Minute},
+		{name: "past-date", retryAfter: "Wed, 21 Oct 2015 07:28:00 GMT", expMin: 0, expMax: 0},
+		{name: "invalid", retryAfter: "soon", expMin: 0, expMax: 0},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Header:     http.Header{},
+				Body:       http.NoBody,
+			}
+			if tc.retryAfter != "" {
+				resp.Header.Set("Retry-After", tc.retryAfter)
+			}
+			err, _ := errors.FromHTTPResponse(resp)
+			if err.RetryAfter < tc.expMin || err.RetryAfter > tc.expMax {
+				t.Errorf("expected RetryAfter within [%v, %v], got %v", tc.expMin, tc.expMax, err.RetryAfter)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// Clock tells the time and waits for time to pass. It allows the passage of
+// time to be faked in tests of code that retries (see the errorstest package).
+type Clock interface {
+	Now() time.Time
+	// Sleep waits for d to elapse, returning ctx.Err() if ctx is done first.
+	Sleep(ctx context.Context, d time.Duration) error
+}
+
+// SystemClock is the Clock backed by the system time. It is used by default.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+func (systemClock) Sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -3,6 +3,8 @@ package errors
 import (
 	"fmt"
 	"net/http"
+	"strconv"
+	"time"
 )
 
 type IsAuthErrChecker interface {
@@ -35,6 +37,10 @@ type IsPreconditionFailedErrChecker interface {
 	IsPreconditionFailedError(error) bool
 }
 
+type IsKindErrChecker interface {
+	IsKindError(error, Kind) bool
+}
+
 type AllErrChecker interface {
 	IsAuthErrChecker
 	IsNotFoundErrChecker
@@ -49,26 +55,85 @@ type ToHTTPResponser interface {
 	ToHTTPResponse(err error, w http.ResponseWriter) (int, bool)
 }
 
-// ErrToHTTP implements ToHTTPResponser interface. It can be embedded in a struct
-// to give said custom struct the ToHTTPResponse method. e.g:
+type ToNegotiatedHTTPResponser interface {
+	ToNegotiatedHTTPResponse(err error, w http.ResponseWriter, r *http.Request) (int, bool)
+}
+
+// ErrToHTTP implements ToHTTPResponser and ToNegotiatedHTTPResponser interfaces.
+// It can be embedded in a struct to give said custom struct the ToHTTPResponse
+// and ToNegotiatedHTTPResponse methods. e.g:
 //  type Custom struct {
 //      ...
 //      errors.ErrToHTTP
 //  }
 type ErrToHTTP struct {
+	// Encoder writes the response body. Defaults to DefaultHTTPEncoder
+	// if nil e.g. ErrToHTTP{Encoder: EncodeJSON} writes JSON bodies.
+	Encoder HTTPEncoder
+	// Strict, if true, only writes explicitly public messages as if
+	// StrictHTTPMessages was set.
+	Strict bool
+}
+
+// ToHTTPResponse attempts to run Error.ToHTTPResponseWith(w, e.Encoder) on the
+// first Error found in err's chain (see Unwrap) that can be written as an HTTP
+// response, returning the result if the call was successful, -1 and false otherwise.
+// The public Fields of the whole of err's chain are written, including those
+// of Errors that wrap the one written.
+func (e ErrToHTTP) ToHTTPResponse(err error, w http.ResponseWriter) (int, bool) {
+	if errC, ok := findErr(err, Error.hasHTTPStatus); ok {
+		return errC.withChainFields(err).writeHTTP(w, nil, e.Encoder, e.Strict)
+	}
+	return -1, false
 }
 
-// ToHTTPResponse attempts to run Error.ToHTTPResponse(w) returning
-// the result if the call was successful, -1 and false otherwise.
-func (e ErrToHTTP) ToHTTPResponse(err error, w http.ResponseWriter) (int, bool) {
-	if err, ok := err.(Error); ok {
-		return err.ToHTTPResponse(w)
+// ToNegotiatedHTTPResponse is like ToHTTPResponse but picks the HTTPEncoder
+// according to the Accept header of r (see NegotiateHTTPEncoder), falling back
+// to e.Encoder.
+func (e ErrToHTTP) ToNegotiatedHTTPResponse(err error, w http.ResponseWriter, r *http.Request) (int, bool) {
+	if errC, ok := findErr(err, Error.hasHTTPStatus); ok {
+		return errC.withChainFields(err).writeNegotiatedHTTP(w, r, e.Encoder, e.Strict)
 	}
 	return -1, false
 }
 
+// Class sentinels to be used with errors.Is e.g:
+//
+//	if errors.Is(err, typederrs.ErrNotFound) {
+//	    ...
+//	}
+//
+// An Error matches the sentinel of every class it belongs to, which means a
+// Forbidden or Unauthorized error also matches ErrAuth.
+var (
+	ErrClient             error = kindErr(KindClient)
+	ErrNotFound           error = kindErr(KindNotFound)
+	ErrNotImplemented     error = kindErr(KindNotImplemented)
+	ErrAuth               error = kindErr(KindAuth)
+	ErrUnauthorized       error = kindErr(KindUnauthorized)
+	ErrForbidden          error = kindErr(KindForbidden)
+	ErrRetryable          error = kindErr(KindRetryable)
+	ErrConflict           error = kindErr(KindConflict)
+	ErrPreconditionFailed error = kindErr(KindPreconditionFailed)
+)
+
+// kindErr is the type of the class sentinels.
+type kindErr Kind
+
+func (k kindErr) Error() string {
+	return Kind(k).String()
+}
+
 // Error implements the Error interface and helps distinguish whether an error
 // is a client error or an auth error.
+//
+// The class of an Error is its Kind, which the New... and Wrap... functions
+// set. The boolean Is...Err flags are retained for backwards compatibility;
+// they are set to match the Kind by the constructors and are honoured when
+// set directly (see Kind for how they resolve).
+//
+// An Error may wrap a Cause, in which case the Cause is reachable through
+// Unwrap and hence errors.Is and errors.As.
 type Error struct {
 	IsAuthErr               bool
 	IsUnauthorizedErr       bool
@@ -81,118 +146,181 @@ type Error struct {
 	IsPreconditionFailedErr bool
 	Data                    interface{}
 	HttpMsg                 string
+	Cause                   error
+	// HttpDetails is included as is in structured (e.g. JSON) HTTP
+	// response bodies. Like HttpMsg, it is meant for the client so should
+	// not contain internal details.
+	HttpDetails interface{}
+	// RetryAfter, if greater than zero, is how long to wait before retrying
+	// a Retryable error. It is respected by DoWithRetries and written as the
+	// Retry-After header of 503 and 429 responses, including those of Errors
+	// that wrap this one.
+	RetryAfter time.Duration
+
+	kind   Kind
+	stack  *stack
+	fields *[]Field
 }
 
 // Error returns the error message of the error (without the distinguishing flags
-// such as client error).
+// such as client error). If the error has a Cause, the Cause's message is
+// appended to that of the error.
 func (e Error) Error() string {
-	return fmt.Sprint(e.Data)
+	if e.Cause == nil {
+		return fmt.Sprint(e.Data)
+	}
+	if e.Data == nil {
+		return e.Cause.Error()
+	}
+	return fmt.Sprintf("%v: %v", e.Data, e.Cause)
+}
+
+// Unwrap returns the Cause of the error, nil if there is none.
+func (e Error) Unwrap() error {
+	return e.Cause
+}
+
+// Is returns true if target is the sentinel of a class this error belongs to
+// e.g. ErrNotFound for a not found error. This allows the class of an error
+// to be checked using errors.Is(err, ErrNotFound).
+func (e Error) Is(target error) bool {
+	k, ok := target.(kindErr)
+	return ok && e.hasKind(Kind(k))
+}
+
+// Kind returns the class of the error. For an Error that was not created
+// using the New... or Wrap... functions, the Kind is resolved from the legacy
+// flags in this order of precedence: IsForbiddenErr, IsUnauthorizedErr,
+// IsAuthErr, IsClErr, IsNotFoundErr, IsNotImplementedErr, IsRetryableErr,
+// IsConflictErr, IsPreconditionFailedErr. The accessors (Client(),
+// NotFound()...) on the other hand report every flag that is set.
+func (e Error) Kind() Kind {
+	if e.kind != KindUnknown {
+		return e.kind
+	}
+	for _, k := range legacyKindPrecedence {
+		if e.legacyFlag(k) {
+			return k
+		}
+	}
+	return KindUnknown
 }
 
 // Client returns true if this is a client error.
 func (e Error) Client() bool {
-	return e.IsClErr
+	return e.hasKind(KindClient)
 }
 
 // ToHTTPResp writes the content of the error to w while setting the HTTP status
-// code to match the type of error received. Returns the HTTP status code
-// assigned and true if error was written, -1 and false otherwise.
+// code to match the type of error received. The body is written using
+// DefaultHTTPEncoder. The message written is HttpMsg if set, otherwise
+// Error(), or the Kind's HTTPMessage if StrictHTTPMessages is set. Returns the
+// HTTP status code assigned and true if error was written, -1 and false
+// otherwise.
 func (e Error) ToHTTPResponse(w http.ResponseWriter) (int, bool) {
+	return e.ToHTTPResponseWith(w, nil)
+}
 
-	msg := e.HttpMsg
-	if msg == "" {
-		msg = e.Error()
-	}
+// ToHTTPResponseWith is like ToHTTPResponse but writes the body using enc.
+// A nil enc means DefaultHTTPEncoder.
+func (e Error) ToHTTPResponseWith(w http.ResponseWriter, enc HTTPEncoder) (int, bool) {
+	return e.writeHTTP(w, nil, enc, false)
+}
 
-	if e.IsAuthErr || e.IsForbiddenErr || e.IsUnauthorizedErr {
-		if e.IsForbiddenErr {
-			http.Error(w, msg, http.StatusForbidden)
-			return http.StatusForbidden, true
-		}
-		http.Error(w, msg, http.StatusUnauthorized)
-		return http.StatusUnauthorized, true
-	}
+// ToNegotiatedHTTPResponse is like ToHTTPResponse but picks the HTTPEncoder
+// according to the Accept header of r (see NegotiateHTTPEncoder).
+func (e Error) ToNegotiatedHTTPResponse(w http.ResponseWriter, r *http.Request) (int, bool) {
+	return e.writeNegotiatedHTTP(w, r, nil, false)
+}
 
-	if e.IsClErr {
-		http.Error(w, msg, http.StatusBadRequest)
-		return http.StatusBadRequest, true
-	}
+// writeHTTP writes e as the response to r (which may be nil) using enc,
+// DefaultHTTPEncoder if enc is nil. strict is as for ErrToHTTP.Strict.
+func (e Error) writeHTTP(w http.ResponseWriter, r *http.Request, enc HTTPEncoder, strict bool) (int, bool) {
 
-	if e.IsNotFoundErr {
-		http.Error(w, msg, http.StatusNotFound)
-		return http.StatusNotFound, true
+	code := e.httpStatus()
+	if code < 0 {
+		return -1, false
 	}
 
-	if e.IsNotImplementedErr {
-		http.Error(w, msg, http.StatusNotImplemented)
-		return http.StatusNotImplemented, true
+	msg := e.HttpMsg
+	if msg == "" {
+		if strict || StrictHTTPMessages {
+			msg = e.Kind().HTTPMessage()
+		} else {
+			msg = e.Error()
+		}
 	}
 
-	if e.IsRetryableErr {
-		http.Error(w, msg, http.StatusServiceUnavailable)
-		return http.StatusServiceUnavailable, true
+	if after, ok := RetryAfter(e); ok &&
+		(code == http.StatusServiceUnavailable || code == http.StatusTooManyRequests) {
+		secs := (after + time.Second - 1) / time.Second
+		w.Header().Set("Retry-After", strconv.FormatInt(int64(secs), 10))
 	}
 
-	if e.IsConflictErr {
-		http.Error(w, msg, http.StatusConflict)
-		return http.StatusConflict, true
+	if enc == nil {
+		enc = DefaultHTTPEncoder
 	}
+	enc(w, r, code, msg, e)
+	return code, true
+}
 
-	if e.IsPreconditionFailedErr {
-		http.Error(w, msg, http.StatusPreconditionFailed)
-		return http.StatusPreconditionFailed, true
-	}
+// httpStatus returns the HTTP status code matching the type of the error,
+// -1 if the error has no type.
+func (e Error) httpStatus() int {
+	return e.Kind().HTTPStatus()
+}
 
-	return -1, false
+func (e Error) hasHTTPStatus() bool {
+	return e.httpStatus() >= 0
 }
 
 // NotImplemented returns true if the functionality requested is not implemented.
 func (e Error) NotImplemented() bool {
-	return e.IsNotImplementedErr
+	return e.hasKind(KindNotImplemented)
 }
 
 // Auth returns true if this is an auth error.
 func (e Error) Auth() bool {
-	return e.IsAuthErr || e.IsUnauthorizedErr || e.IsForbiddenErr
+	return e.hasKind(KindAuth)
 }
 
 // Unauthorized returns true if this is an Unauthorized error.
 func (e Error) Unauthorized() bool {
-	return e.IsUnauthorizedErr
+	return e.hasKind(KindUnauthorized)
 }
 
 // Forbidden returns true if this is a Forbidden error.
 func (e Error) Forbidden() bool {
-	return e.IsForbiddenErr
+	return e.hasKind(KindForbidden)
 }
 
 // NotFound returns true if this is error denotes that a resource
 // being fetched was not found.
 func (e Error) NotFound() bool {
-	return e.IsNotFoundErr
+	return e.hasKind(KindNotFound)
 }
 
 // Retryable returns true if this error is not permanent and should
 // be retried
 func (e Error) Retryable() bool {
-	return e.IsRetryableErr
+	return e.hasKind(KindRetryable)
 }
 
 // Conflict returns true if this error denotes a conflict in resources a la
 // HTTPs 409 error
 func (e Error) Conflict() bool {
-	return e.IsConflictErr
+	return e.hasKind(KindConflict)
 }
 
 // PreconditionFailed returns true if this error denotes a
 // precondition failure in resources a la HTTPs 412 error
 func (e Error) PreconditionFailed() bool {
-	return e.IsPreconditionFailedErr
+	return e.hasKind(KindPreconditionFailed)
 }
 
 // New creates a new error.
 func New(data interface{}) Error {
-	return Error{Data: data}
+	return newError(KindUnknown, nil, "", data)
 }
 
 // Newf creates a new error with fmt.Printf formatting.
@@ -204,7 +332,7 @@ func Newf(format string, a ...interface{}) Error {
 // NewWithHttp creates a new error containing a http specific
 // error message.
 func NewWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg}
+	return newError(KindUnknown, nil, httpMsg, data)
 }
 
 // NewWithHttp creates a new error containing a http specific
@@ -216,7 +344,7 @@ func NewWithHttpf(httpMsg string, format string, a ...interface{}) Error {
 
 // NewClient creates a new client error.
 func NewClient(data interface{}) Error {
-	return Error{Data: data, IsClErr: true}
+	return newError(KindClient, nil, "", data)
 }
 
 // NewClientf creates a new client error with fmt.Printf style formatting.
@@ -228,7 +356,7 @@ func NewClientf(format string, a ...interface{}) Error {
 // NewClientWithHttp creates a new error containing a http specific
 // error message.
 func NewClientWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg, IsClErr: true}
+	return newError(KindClient, nil, httpMsg, data)
 }
 
 // NewClientWithHttp creates a new error containing a http specific
@@ -240,20 +368,20 @@ func NewClientWithHttpf(httpMsg string, format string, a ...interface{}) Error {
 
 // NewNotImplemented creates a new not implemented error.
 func NewNotImplemented() Error {
-	return Error{IsNotImplementedErr: true, Data: "not implemented"}
+	return newError(KindNotImplemented, nil, "", "not implemented")
 }
 
 // NewNotImplementedf creates a new not implemented error with fmt.Printf
 // style formatting.
 func NewNotImplementedf(format string, a ...interface{}) Error {
 	data := fmt.Sprintf(format, a...)
-	return Error{Data: data, IsNotImplementedErr: true}
+	return newError(KindNotImplemented, nil, "", data)
 }
 
 // NewNotImplementedWithHttp creates a new error containing a http specific
 // error message.
 func NewNotImplementedWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg, IsNotImplementedErr: true}
+	return newError(KindNotImplemented, nil, httpMsg, data)
 }
 
 // NewNotImplementedWithHttp creates a new error containing a http specific
@@ -267,7 +395,7 @@ func NewNotImplementedWithHttpf(httpMsg string, format string, a ...interface{})
 // error. Use NewForbidden(string) or NewUnauthorized(string) to establish
 // a more specific Auth error.
 func NewAuth(data interface{}) Error {
-	return Error{Data: data, IsAuthErr: true}
+	return newError(KindAuth, nil, "", data)
 }
 
 // NewAuthf creates a new auth error with fmt.Printf style formatting.
@@ -279,7 +407,7 @@ func NewAuthf(format string, a ...interface{}) Error {
 // NewAuthWithHttp creates a new error containing a http specific
 // error message.
 func NewAuthWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg, IsAuthErr: true}
+	return newError(KindAuth, nil, httpMsg, data)
 }
 
 // NewWithHttp creates a new error containing a http specific
@@ -292,7 +420,7 @@ func NewAuthWithHttpf(httpMsg string, format string, a ...interface{}) Error {
 // NewForbidden creates a new forbidden auth error a la 403 (http.StatusForbidden) error.
 // This will also resolve as an Auth error.
 func NewForbidden(data interface{}) Error {
-	return Error{Data: data, IsAuthErr: true, IsForbiddenErr: true}
+	return newError(KindForbidden, nil, "", data)
 }
 
 // NewForbiddenf creates a new forbidden auth error with fmt.Printf style formatting.
@@ -305,7 +433,7 @@ func NewForbiddenf(format string, a ...interface{}) Error {
 // NewForbiddentWithHttp creates a new error containing a http specific
 // error message.
 func NewForbiddentWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg, IsForbiddenErr: true}
+	return newError(KindForbidden, nil, httpMsg, data)
 }
 
 // NewForbiddentWithHttp creates a new error containing a http specific
@@ -318,7 +446,7 @@ func NewForbiddentWithHttpf(httpMsg string, format string, a ...interface{}) Err
 // NewUnauthorized creates a new unauthorized auth error a la 401 (http.StatusUnauthorized) error.
 // This will also resolve as an Auth error.
 func NewUnauthorized(data interface{}) Error {
-	return Error{Data: data, IsAuthErr: true, IsUnauthorizedErr: true}
+	return newError(KindUnauthorized, nil, "", data)
 }
 
 // NewUnauthorizedf creates a new unauthorized auth error with fmt.Printf style formatting.
@@ -331,7 +459,7 @@ func NewUnauthorizedf(format string, a ...interface{}) Error {
 // NewUnauthorizedWithHttp creates a new error containing a http specific
 // error message.
 func NewUnauthorizedWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg, IsUnauthorizedErr: true}
+	return newError(KindUnauthorized, nil, httpMsg, data)
 }
 
 // NewWithHttp creates a new error containing a http specific
@@ -343,7 +471,7 @@ func NewUnauthorizedWithHttpf(httpMsg string, format string, a ...interface{}) E
 
 // NewNotFound creates a new not found error.
 func NewNotFound(data interface{}) Error {
-	return Error{Data: data, IsNotFoundErr: true}
+	return newError(KindNotFound, nil, "", data)
 }
 
 // NewNotFoundf creates a new not found error with fmt.Printf style formatting.
@@ -355,7 +483,7 @@ func NewNotFoundf(format string, a ...interface{}) Error {
 // NewNotFoundWithHttp creates a new error containing a http specific
 // error message.
 func NewNotFoundWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg, IsNotFoundErr: true}
+	return newError(KindNotFound, nil, httpMsg, data)
 }
 
 // NewNotFoundWithHttp creates a new error containing a http specific
@@ -367,7 +495,7 @@ func NewNotFoundWithHttpf(httpMsg string, format string, a ...interface{}) Error
 
 // NewRetryable creates a new retryable error.
 func NewRetryable(data interface{}) Error {
-	return Error{Data: data, IsRetryableErr: true}
+	return newError(KindRetryable, nil, "", data)
 }
 
 // NewRetryablef creates a new retryable error with fmt.Printf style formatting.
@@ -379,7 +507,7 @@ func NewRetryablef(format string, a ...interface{}) Error {
 // NewRetryableWithHttp creates a new error containing a http specific
 // error message.
 func NewRetryableWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg, IsRetryableErr: true}
+	return newError(KindRetryable, nil, httpMsg, data)
 }
 
 // NewRetryableWithHttp creates a new error containing a http specific
@@ -389,9 +517,31 @@ func NewRetryableWithHttpf(httpMsg string, format string, a ...interface{}) Erro
 	return NewRetryableWithHttp(httpMsg, data)
 }
 
+// NewRetryableAfter creates a new retryable error that should not be retried
+// before after has elapsed.
+func NewRetryableAfter(after time.Duration, data interface{}) Error {
+	e := newError(KindRetryable, nil, "", data)
+	e.RetryAfter = after
+	return e
+}
+
+// NewRetryableAfterf creates a new retryable error that should not be retried
+// before after has elapsed with fmt.Printf style formatting.
+func NewRetryableAfterf(after time.Duration, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return NewRetryableAfter(after, data)
+}
+
+// RetryAfter returns the RetryAfter of the first Error in err's chain that
+// has one, false if none does.
+func RetryAfter(err error) (time.Duration, bool) {
+	errC, ok := findErr(err, func(e Error) bool { return e.RetryAfter > 0 })
+	return errC.RetryAfter, ok
+}
+
 // NewConflict creates a new Conflict error.
 func NewConflict(data interface{}) Error {
-	return Error{Data: data, IsConflictErr: true}
+	return newError(KindConflict, nil, "", data)
 }
 
 // NewConflictf creates a new Conflict error with fmt.Printf style formatting.
@@ -403,7 +553,7 @@ func NewConflictf(format string, a ...interface{}) Error {
 // NewConflictWithHttp creates a new error containing a http specific
 // error message.
 func NewConflictWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg, IsConflictErr: true}
+	return newError(KindConflict, nil, httpMsg, data)
 }
 
 // NewConflictWithHttp creates a new error containing a http specific
@@ -415,7 +565,7 @@ func NewConflictWithHttpf(httpMsg string, format string, a ...interface{}) Error
 
 // NewPreconditionFailed creates a new PreconditionFailed error.
 func NewPreconditionFailed(data interface{}) Error {
-	return Error{Data: data, IsPreconditionFailedErr: true}
+	return newError(KindPreconditionFailed, nil, "", data)
 }
 
 // NewPreconditionFailedf creates a new PreconditionFailed error with fmt.Printf style formatting.
@@ -427,7 +577,7 @@ func NewPreconditionFailedf(format string, a ...interface{}) Error {
 // NewPreconditionFailedWithHttp creates a new error containing a http specific
 // error message.
 func NewPreconditionFailedWithHttp(httpMsg string, data interface{}) Error {
-	return Error{Data: data, HttpMsg: httpMsg, IsPreconditionFailedErr: true}
+	return newError(KindPreconditionFailed, nil, httpMsg, data)
 }
 
 // NewPreconditionFailedWithHttp creates a new error containing a http specific
@@ -448,8 +598,8 @@ type ClErrCheck struct {
 
 // IsClientError returns true if the supplied error is a client error, false otherwise.
 func (c *ClErrCheck) IsClientError(err error) bool {
-	errC, ok := err.(Error)
-	return ok && errC.Client()
+	_, ok := findErr(err, Error.Client)
+	return ok
 }
 
 // NotImplErrCheck implements the NotImplErrChecker interface. It can be embedded in a custom struct to
@@ -463,8 +613,8 @@ type NotImplErrCheck struct {
 
 // IsNotImplementedError returns true if the supplied error is a client error, false otherwise.
 func (c *NotImplErrCheck) IsNotImplementedError(err error) bool {
-	errC, ok := err.(Error)
-	return ok && errC.NotImplemented()
+	_, ok := findErr(err, Error.NotImplemented)
+	return ok
 }
 
 // AuthErrCheck implements the AuthErrChecker interface. It can be embedded in a custom struct to
@@ -480,22 +630,22 @@ type AuthErrCheck struct {
 // IsAuthError returns true if the supplied error is an
 // authentication/authorization error, false otherwise.
 func (c *AuthErrCheck) IsAuthError(err error) bool {
-	errC, ok := err.(Error)
-	return ok && errC.Auth()
+	_, ok := findErr(err, Error.Auth)
+	return ok
 }
 
 // IsAuthError returns true if the supplied error is an
 // authentication/authorization error, false otherwise.
 func (c *AuthErrCheck) IsForbiddenError(err error) bool {
-	errC, ok := err.(Error)
-	return ok && errC.Forbidden()
+	_, ok := findErr(err, Error.Forbidden)
+	return ok
 }
 
 // IsAuthError returns true if the supplied error is an
 // authentication/authorization error, false otherwise.
 func (c *AuthErrCheck) IsUnauthorizedError(err error) bool {
-	errC, ok := err.(Error)
-	return ok && errC.Unauthorized()
+	_, ok := findErr(err, Error.Unauthorized)
+	return ok
 }
 
 // NotFoundErrCheck implements the NotFoundErrChecker interface. It can be
@@ -510,8 +660,8 @@ type NotFoundErrCheck struct {
 
 // IsNotFoundError returns true if the supplied error is an not found error, false otherwise.
 func (c *NotFoundErrCheck) IsNotFoundError(err error) bool {
-	errC, ok := err.(Error)
-	return ok && errC.NotFound()
+	_, ok := findErr(err, Error.NotFound)
+	return ok
 }
 
 // RetryableErrCheck implements the RetryableErrChecker interface. It can be embedded in a custom struct to
@@ -525,8 +675,8 @@ type RetryableErrCheck struct {
 
 // IsRetryableError returns true if the supplied error retryable, false otherwise.
 func (c *RetryableErrCheck) IsRetryableError(err error) bool {
-	errC, ok := err.(Error)
-	return ok && errC.Retryable()
+	_, ok := findErr(err, Error.Retryable)
+	return ok
 }
 
 // ConflictErrCheck implements the ConflictErrChecker interface. It can be embedded in a custom struct to
@@ -540,8 +690,8 @@ type ConflictErrCheck struct {
 
 // IsConflictError returns true if the supplied error is a Conflict error, false otherwise.
 func (c *ConflictErrCheck) IsConflictError(err error) bool {
-	errC, ok := err.(Error)
-	return ok && errC.Conflict()
+	_, ok := findErr(err, Error.Conflict)
+	return ok
 }
 
 // PreconditionFailedErrCheck implements the PreconditionFailedErrChecker interface.
@@ -556,12 +706,32 @@ type PreconditionFailedErrCheck struct {
 
 // IsConflictError returns true if the supplied error is a Conflict error, false otherwise.
 func (c *PreconditionFailedErrCheck) IsPreconditionFailedError(err error) bool {
-	errC, ok := err.(Error)
-	return ok && errC.PreconditionFailed()
+	_, ok := findErr(err, Error.PreconditionFailed)
+	return ok
+}
+
+// KindErrCheck implements the IsKindErrChecker interface. It can be embedded in a
+// custom struct to give the custom struct the extra method
+// IsKindError(err error, k Kind), which is most useful for Kinds added
+// using RegisterKind. e.g:
+//  type Custom struct {
+//      ...
+//      errors.KindErrCheck
+//  }
+type KindErrCheck struct {
+}
+
+// IsKindError returns true if the supplied error is of Kind k or a Kind that
+// descends from k, false otherwise.
+func (c *KindErrCheck) IsKindError(err error, k Kind) bool {
+	_, ok := findErr(err, func(e Error) bool { return e.hasKind(k) })
+	return ok
 }
 
 // AllErrCheck implements the AllErrChecker interface. It can be embedded in a custom struct to
-// give said custom struct the extra Is...Error(err error) methods. e.g:
+// give said custom struct the extra Is...Error(err error) methods. Like the
+// individual checkers, the methods search the whole chain of the supplied error
+// (e.g. one wrapped using fmt.Errorf("...: %w", err) or errors.Join). e.g:
 //  type Custom struct {
 //      ...
 //      errors.AllErrCheck
@@ -574,4 +744,30 @@ type AllErrCheck struct {
 	RetryableErrCheck
 	ConflictErrCheck
 	PreconditionFailedErrCheck
+	KindErrCheck
+}
+
+// findErr walks err's chain depth first, following both Unwrap() error and
+// Unwrap() []error (as produced by errors.Join), and returns the first Error
+// for which match returns true.
+func findErr(err error, match func(Error) bool) (Error, bool) {
+	for err != nil {
+		if errC, ok := err.(Error); ok && match(errC) {
+			return errC, true
+		}
+		switch u := err.(type) {
+		case interface{ Unwrap() []error }:
+			for _, err := range u.Unwrap() {
+				if errC, ok := findErr(err, match); ok {
+					return errC, true
+				}
+			}
+			return Error{}, false
+		case interface{ Unwrap() error }:
+			err = u.Unwrap()
+		default:
+			return Error{}, false
+		}
+	}
+	return Error{}, false
 }
@@ -1,7 +1,10 @@
 package errors_test
 
 import (
+	goerrors "errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/tomogoma/go-typed-errors"
@@ -494,6 +497,138 @@ func TestPreconditionFailedErrCheck_IsPreconditionFailedError(t *testing.T) {
 	}
 }
 
+func TestError_Unwrap(t *testing.T) {
+	cause := goerrors.New("connection reset")
+	err := errors.Error{Data: "reading row", Cause: cause, IsRetryableErr: true}
+	if err.Unwrap() != cause {
+		t.Errorf("expected Unwrap() to return the cause, got %v", err.Unwrap())
+	}
+	if !goerrors.Is(err, cause) {
+		t.Errorf("expected errors.Is to find the cause in %v", err)
+	}
+	if err.Error() != "reading row: connection reset" {
+		t.Errorf("expected error message '%s', got '%s'",
+			"reading row: connection reset", err.Error())
+	}
+	if errors.New("no cause").Unwrap() != nil {
+		t.Errorf("expected nil Unwrap() on an error without a cause")
+	}
+}
+
+func TestAllErrCheck_wrappedChain(t *testing.T) {
+	checker := errors.AllErrCheck{}
+	tt := []struct {
+		name string
+		err  error
+	}{
+		{name: "fmt-wrapped", err: fmt.Errorf("loading user: %w", errors.NewNotFound("no user"))},
+		{name: "double-wrapped", err: fmt.Errorf("handler: %w",
+			fmt.Errorf("loading user: %w", errors.NewNotFound("no user")))},
+		{name: "joined", err: goerrors.Join(goerrors.New("other"), errors.NewNotFound("no user"))},
+		{name: "joined-then-wrapped", err: fmt.Errorf("handler: %w",
+			goerrors.Join(goerrors.New("other"), errors.NewNotFound("no user")))},
+		{name: "as-cause", err: errors.Error{Data: "loading user", Cause: errors.NewNotFound("no user")}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if !checker.IsNotFoundError(tc.err) {
+				t.Errorf("expected IsNotFoundError true for %v", tc.err)
+			}
+			if checker.IsClientError(tc.err) {
+				t.Errorf("expected IsClientError false for %v", tc.err)
+			}
+		})
+	}
+	if checker.IsNotFoundError(nil) {
+		t.Errorf("expected IsNotFoundError false for nil")
+	}
+	if checker.IsNotFoundError(fmt.Errorf("plain: %w", goerrors.New("no type"))) {
+		t.Errorf("expected IsNotFoundError false for untyped chain")
+	}
+}
+
+func TestErrToHTTP_ToHTTPResponse_wrappedChain(t *testing.T) {
+	tt := []struct {
+		name       string
+		err        error
+		expStatus  int
+		expWritten bool
+	}{
+		{name: "direct", err: errors.NewNotFound("none"),
+			expStatus: http.StatusNotFound, expWritten: true},
+		{name: "fmt-wrapped", err: fmt.Errorf("loading: %w", errors.NewNotFound("none")),
+			expStatus: http.StatusNotFound, expWritten: true},
+		{name: "untyped-outer-Error", err: errors.Error{Data: "outer", Cause: errors.NewConflict("dup")},
+			expStatus: http.StatusConflict, expWritten: true},
+		{name: "joined", err: goerrors.Join(goerrors.New("other"), errors.NewForbidden("nope")),
+			expStatus: http.StatusForbidden, expWritten: true},
+		{name: "untyped", err: fmt.Errorf("plain: %w", goerrors.New("no type")),
+			expStatus: -1, expWritten: false},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			status, ok := errors.ErrToHTTP{}.ToHTTPResponse(tc.err, w)
+			if ok != tc.expWritten {
+				t.Fatalf("expected written %t, got %t", tc.expWritten, ok)
+			}
+			if status != tc.expStatus {
+				t.Errorf("expected status %d, got %d", tc.expStatus, status)
+			}
+			if ok && w.Code != tc.expStatus {
+				t.Errorf("expected response status %d, got %d", tc.expStatus, w.Code)
+			}
+		})
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	tt := []struct {
+		name     string
+		err      error
+		matches  []error
+		excludes []error
+	}{
+		{name: "client", err: errors.NewClient("bad"),
+			matches: []error{errors.ErrClient}, excludes: []error{errors.ErrNotFound, errors.ErrAuth}},
+		{name: "not-found", err: errors.NewNotFound("none"),
+			matches: []error{errors.ErrNotFound}, excludes: []error{errors.ErrClient}},
+		{name: "not-implemented", err: errors.NewNotImplemented(),
+			matches: []error{errors.ErrNotImplemented}, excludes: []error{errors.ErrRetryable}},
+		{name: "auth", err: errors.NewAuth("who"),
+			matches:  []error{errors.ErrAuth},
+			excludes: []error{errors.ErrForbidden, errors.ErrUnauthorized}},
+		{name: "forbidden", err: errors.NewForbidden("nope"),
+			matches: []error{errors.ErrForbidden, errors.ErrAuth}, excludes: []error{errors.ErrUnauthorized}},
+		{name: "unauthorized", err: errors.NewUnauthorized("who"),
+			matches: []error{errors.ErrUnauthorized, errors.ErrAuth}, excludes: []error{errors.ErrForbidden}},
+		{name: "retryable", err: errors.NewRetryable("later"),
+			matches: []error{errors.ErrRetryable}, excludes: []error{errors.ErrConflict}},
+		{name: "conflict", err: errors.NewConflict("dup"),
+			matches: []error{errors.ErrConflict}, excludes: []error{errors.ErrPreconditionFailed}},
+		{name: "precondition-failed", err: errors.NewPreconditionFailed("stale"),
+			matches: []error{errors.ErrPreconditionFailed}, excludes: []error{errors.ErrConflict}},
+		{name: "wrapped", err: fmt.Errorf("loading: %w", errors.NewNotFound("none")),
+			matches: []error{errors.ErrNotFound}, excludes: []error{errors.ErrClient}},
+		{name: "untyped", err: errors.New("plain"),
+			excludes: []error{errors.ErrClient, errors.ErrNotFound, errors.ErrAuth, errors.ErrRetryable}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, target := range tc.matches {
+				if !goerrors.Is(tc.err, target) {
+					t.Errorf("expected errors.Is(%v, %v) true", tc.err, target)
+				}
+			}
+			for _, target := range tc.excludes {
+				if goerrors.Is(tc.err, target) {
+					t.Errorf("expected errors.Is(%v, %v) false", tc.err, target)
+				}
+			}
+		})
+	}
+}
+
 func messageTestCases() []testCase {
 	return []testCase{
 		{name: "has-message", message: "this error message"},
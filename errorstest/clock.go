@@ -0,0 +1,73 @@
+// Package errorstest provides utilities for testing code that uses the
+// go-typed-errors package.
+package errorstest
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// FakeClock is a Clock (see github.com/tomogoma/go-typed-errors) whose time
+// only moves when Sleep or Advance is called. Sleep returns immediately,
+// which allows retry behaviour to be tested instantly and deterministically
+// e.g:
+//
+//	clock := errorstest.NewFakeClock(time.Now())
+//	err := typederrs.DoWithRetries(doer, typederrs.RetryWithClock(clock))
+//	// assert on clock.Sleeps()
+//
+// FakeClock is safe for concurrent use.
+type FakeClock struct {
+	// OnSleep, if set, is called by Sleep with the duration being slept
+	// before the clock is advanced. It can e.g. cancel the context passed to
+	// Sleep to simulate cancellation during a wait.
+	OnSleep func(d time.Duration)
+
+	mu     sync.Mutex
+	now    time.Time
+	sleeps []time.Duration
+}
+
+// NewFakeClock creates a FakeClock whose time is now.
+func NewFakeClock(now time.Time) *FakeClock {
+	return &FakeClock{now: now}
+}
+
+// Now returns the current time of the clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// Sleep records d and advances the clock by d, unless ctx is done (after
+// calling OnSleep) in which case it returns ctx.Err().
+func (c *FakeClock) Sleep(ctx context.Context, d time.Duration) error {
+	if c.OnSleep != nil {
+		c.OnSleep(d)
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sleeps = append(c.sleeps, d)
+	c.now = c.now.Add(d)
+	return nil
+}
+
+// Advance moves the clock forward by d.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Sleeps returns the durations of the completed calls to Sleep in the order
+// they were made.
+func (c *FakeClock) Sleeps() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]time.Duration(nil), c.sleeps...)
+}
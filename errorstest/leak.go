@@ -0,0 +1,64 @@
+package errorstest
+
+import (
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	typederrs "github.com/tomogoma/go-typed-errors"
+)
+
+// AssertNoLeak fails t if the body or headers of the response recorded by w
+// contain the internal message of err i.e. the message of err or of any
+// error it wraps, except for messages explicitly made public as the HttpMsg of
+// an Error in the chain e.g:
+//
+//	w := httptest.NewRecorder()
+//	typederrs.ErrToHTTP{Strict: true}.ToHTTPResponse(err, w)
+//	errorstest.AssertNoLeak(t, w, err)
+func AssertNoLeak(t testing.TB, w *httptest.ResponseRecorder, err error) {
+	t.Helper()
+	public := make(map[string]bool)
+	var internal []string
+	walk(err, func(err error) {
+		if e, ok := err.(typederrs.Error); ok {
+			if e.HttpMsg != "" {
+				public[e.HttpMsg] = true
+			}
+			if e.Data != nil {
+				internal = append(internal, fmt.Sprint(e.Data))
+			}
+		}
+		internal = append(internal, err.Error())
+	})
+	var headers strings.Builder
+	w.Header().Write(&headers)
+	for _, msg := range internal {
+		if msg == "" || public[msg] {
+			continue
+		}
+		if strings.Contains(w.Body.String(), msg) {
+			t.Errorf("response body leaks internal message %q: %s", msg, w.Body.String())
+		}
+		if strings.Contains(headers.String(), msg) {
+			t.Errorf("response headers leak internal message %q: %s", msg, headers.String())
+		}
+	}
+}
+
+// walk calls f with err and every error it wraps.
+func walk(err error, f func(error)) {
+	if err == nil {
+		return
+	}
+	f(err)
+	switch u := err.(type) {
+	case interface{ Unwrap() error }:
+		walk(u.Unwrap(), f)
+	case interface{ Unwrap() []error }:
+		for _, err := range u.Unwrap() {
+			walk(err, f)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+)
+
+// Field is a key/value pair of context attached to an Error e.g. the ID of the
+// user a request failed for. Fields are always logged (see Error.LogValue and
+// Format) but only written in HTTP response bodies if Public.
+type Field struct {
+	Key   string
+	Value interface{}
+	// Public fields are meant for the client and are written as the fields
+	// of a JSONError and as extension members by EncodeProblem.
+	Public bool
+}
+
+// NewField creates a new Field that is not written in HTTP responses.
+func NewField(key string, value interface{}) Field {
+	return Field{Key: key, Value: value}
+}
+
+// NewPublicField creates a new Field that is written in HTTP responses.
+func NewPublicField(key string, value interface{}) Field {
+	return Field{Key: key, Value: value, Public: true}
+}
+
+// WithFields returns a copy of the error with fields attached, replacing any
+// of its fields with the same key e.g:
+//
+//	return typederrs.WrapNotFound(err, "fetch order").WithFields(
+//	    typederrs.NewPublicField("order_id", id),
+//	    typederrs.NewField("table", "orders"),
+//	)
+//
+// The error's fields are never modified, so copies of it do not see the fields
+// attached to this one.
+func (e Error) WithFields(fields ...Field) Error {
+	if len(fields) == 0 {
+		return e
+	}
+	var merged []Field
+	if e.fields != nil {
+		merged = append(merged, *e.fields...)
+	}
+	for _, f := range fields {
+		merged = setField(merged, f)
+	}
+	e.fields = &merged
+	return e
+}
+
+// WithField returns a copy of the error with a Field created by NewField
+// attached. See WithFields.
+func (e Error) WithField(key string, value interface{}) Error {
+	return e.WithFields(NewField(key, value))
+}
+
+// WithPublicField returns a copy of the error with a Field created by
+// NewPublicField attached. See WithFields.
+func (e Error) WithPublicField(key string, value interface{}) Error {
+	return e.WithFields(NewPublicField(key, value))
+}
+
+// Fields returns the fields attached to the error itself, excluding those of
+// its Cause (see the Fields function for those).
+func (e Error) Fields() []Field {
+	if e.fields == nil {
+		return nil
+	}
+	return append([]Field(nil), *e.fields...)
+}
+
+// Fields returns the fields of every Error in err's chain. Where Errors have
+// fields with the same key, that of the outermost Error is returned.
+func Fields(err error) []Field {
+	var fields []Field
+	seen := make(map[string]bool)
+	// Never matching makes findErr visit every Error in the chain.
+	findErr(err, func(e Error) bool {
+		if e.fields == nil {
+			return false
+		}
+		for _, f := range *e.fields {
+			if !seen[f.Key] {
+				seen[f.Key] = true
+				fields = append(fields, f)
+			}
+		}
+		return false
+	})
+	return fields
+}
+
+// withChainFields returns a copy of e whose own fields are the Fields of
+// chain, the error e was found in, such that fields attached to the Errors
+// that wrap e are written along with e's.
+func (e Error) withChainFields(chain error) Error {
+	fields := Fields(chain)
+	if len(fields) == 0 {
+		return e
+	}
+	e.fields = &fields
+	return e
+}
+
+// publicFields returns the public Fields of e's chain keyed by Key, nil if
+// there are none.
+func publicFields(e Error) map[string]interface{} {
+	var public map[string]interface{}
+	for _, f := range Fields(e) {
+		if !f.Public {
+			continue
+		}
+		if public == nil {
+			public = make(map[string]interface{})
+		}
+		public[f.Key] = f.Value
+	}
+	return public
+}
+
+// setField returns fields with f appended, or replacing the field with the
+// same key.
+func setField(fields []Field, f Field) []Field {
+	for i := range fields {
+		if fields[i].Key == f.Key {
+			fields[i] = f
+			return fields
+		}
+	}
+	return append(fields, f)
+}
+
+// fieldAttrs returns the error's own fields as slog attributes.
+func (e Error) fieldAttrs() []interface{} {
+	if e.fields == nil {
+		return nil
+	}
+	attrs := make([]interface{}, len(*e.fields))
+	for i, f := range *e.fields {
+		attrs[i] = slog.Any(f.Key, f.Value)
+	}
+	return attrs
+}
+
+// writeFields writes the error's own fields as space separated key=value
+// pairs.
+func (e Error) writeFields(w io.Writer) {
+	for i, f := range *e.fields {
+		if i > 0 {
+			io.WriteString(w, " ")
+		}
+		fmt.Fprintf(w, "%s=%v", f.Key, f.Value)
+	}
+}
@@ -0,0 +1,106 @@
+package errors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func TestError_WithFields(t *testing.T) {
+	base := errors.NewNotFound("no order").WithField("order_id", 1)
+	err := base.WithField("order_id", 2).WithPublicField("user_id", 7)
+
+	expBase := []errors.Field{errors.NewField("order_id", 1)}
+	if got := base.Fields(); !reflect.DeepEqual(got, expBase) {
+		t.Errorf("expected the original's fields unchanged %v, got %v", expBase, got)
+	}
+	exp := []errors.Field{errors.NewField("order_id", 2), errors.NewPublicField("user_id", 7)}
+	if got := err.Fields(); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+	if err == base {
+		t.Errorf("expected errors with different fields to differ")
+	}
+}
+
+func TestFields(t *testing.T) {
+	inner := errors.NewRetryable("timeout").WithField("table", "orders").WithField("user_id", 1)
+	err := fmt.Errorf("checkout: %w",
+		errors.WrapClient(inner, "bad order").WithPublicField("user_id", 7))
+	exp := []errors.Field{errors.NewPublicField("user_id", 7), errors.NewField("table", "orders")}
+	if got := errors.Fields(err); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+	if got := errors.Fields(errDriver); got != nil {
+		t.Errorf("expected no fields, got %v", got)
+	}
+}
+
+func TestEncodeJSON_fields(t *testing.T) {
+	err := errors.WrapClient(errors.NewRetryable("timeout").WithField("table", "orders"), "bad order").
+		WithPublicField("order_id", 7)
+	w := httptest.NewRecorder()
+	errors.EncodeJSON(w, nil, 400, "bad order", err)
+	var body errors.JSONBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal body '%s': %v", w.Body.String(), err)
+	}
+	exp := map[string]interface{}{"order_id": float64(7)}
+	if !reflect.DeepEqual(body.Error.Fields, exp) {
+		t.Errorf("expected only public fields %v, got %v", exp, body.Error.Fields)
+	}
+}
+
+func TestErrToHTTP_outerFields(t *testing.T) {
+	err := fmt.Errorf("checkout: %w", errors.Wrap(
+		errors.NewNotFound("no order").WithPublicField("order_id", 1).WithPublicField("user_id", 3),
+		"ctx",
+	).WithPublicField("order_id", 7).WithField("table", "orders"))
+
+	w := httptest.NewRecorder()
+	errors.ErrToHTTP{Encoder: errors.EncodeJSON}.ToHTTPResponse(err, w)
+	var body errors.JSONBody
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("unmarshal body '%s': %v", w.Body.String(), err)
+	}
+	exp := map[string]interface{}{"order_id": float64(7), "user_id": float64(3)}
+	if body.Error.Class != "not_found" || !reflect.DeepEqual(body.Error.Fields, exp) {
+		t.Errorf("expected not_found with fields %v, got %s", exp, w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/orders/7", nil)
+	r.Header.Set("Accept", "application/problem+json")
+	errors.ErrToHTTP{}.ToNegotiatedHTTPResponse(err, w, r)
+	var problem map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &problem); err != nil {
+		t.Fatalf("unmarshal body '%s': %v", w.Body.String(), err)
+	}
+	if problem["order_id"] != float64(7) || problem["user_id"] != float64(3) || problem["table"] != nil {
+		t.Errorf("expected public fields as extension members, got %s", w.Body.String())
+	}
+}
+
+func TestError_fieldsLoggedAndFormatted(t *testing.T) {
+	err := errors.NewConflict("duplicate").WithField("table", "orders").WithPublicField("order_id", 7)
+
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("failed", "err", err)
+	errAttr, _ := decodeLogAttr(t, buf.Bytes(), "err").(map[string]interface{})
+	exp := map[string]interface{}{"table": "orders", "order_id": float64(7)}
+	if got := errAttr["fields"]; !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected logged fields %v, got %v", exp, got)
+	}
+
+	expOut := "conflict: duplicate\n\tfields: table=orders order_id=7"
+	if out := fmt.Sprintf("%+v", err); out != expOut {
+		t.Errorf("expected:\n%s\ngot:\n%s", expOut, out)
+	}
+}
@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Format implements fmt.Formatter. The %s, %v and %q verbs format the error as
+// per its Error method. %+v also prints the class of the error, its metadata
+// (HttpMsg, RetryAfter, HttpDetails and Fields), stack trace (see
+// SetStackCapture) and the Cause, itself formatted using %+v, e.g:
+//
+//	not_found: user 7 not found
+//		http message: user not found
+//		fields: user_id=7
+//	main.fetchUser
+//		/src/main.go:42
+//	caused by: sql: no rows in result set
+//
+// %#v prints the Error as a Go struct. Other verbs, widths and flags apply to
+// the Error() string as they did before Error implemented fmt.Formatter.
+func (e Error) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('#') {
+		// plain has no Format method, keeping fmt from recursing.
+		type plain Error
+		out := fmt.Sprintf("%#v", plain(e))
+		io.WriteString(s, strings.Replace(out, "plain{", "Error{", 1))
+		return
+	}
+	if verb == 'v' && s.Flag('+') {
+		e.writeVerbose(s)
+		return
+	}
+	fmt.Fprintf(s, fmt.FormatString(s, verb), e.Error())
+}
+
+// writeVerbose writes e as described for %+v in Format.
+func (e Error) writeVerbose(w io.Writer) {
+	io.WriteString(w, e.Kind().String())
+	if e.Data != nil || e.Cause == nil {
+		fmt.Fprintf(w, ": %v", e.Data)
+	}
+	if e.HttpMsg != "" {
+		fmt.Fprintf(w, "\n\thttp message: %s", e.HttpMsg)
+	}
+	if e.RetryAfter > 0 {
+		fmt.Fprintf(w, "\n\tretry after: %v", e.RetryAfter)
+	}
+	if e.HttpDetails != nil {
+		fmt.Fprintf(w, "\n\thttp details: %+v", e.HttpDetails)
+	}
+	if e.fields != nil {
+		io.WriteString(w, "\n\tfields: ")
+		e.writeFields(w)
+	}
+	writeStack(w, e.StackTrace())
+	if e.Cause != nil {
+		fmt.Fprintf(w, "\ncaused by: %+v", e.Cause)
+	}
+}
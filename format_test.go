@@ -0,0 +1,51 @@
+package errors_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func TestError_Format(t *testing.T) {
+	retryable := errors.NewRetryableAfter(2*time.Second, "db overloaded")
+	notFound := errors.WrapNotFoundWithHttp(retryable, "user not found", "fetch user 7")
+	notFound.HttpDetails = map[string]int{"id": 7}
+	tt := []struct {
+		name   string
+		format string
+		err    error
+		exp    string
+	}{
+		{name: "s", format: "%s", err: notFound, exp: "fetch user 7: db overloaded"},
+		{name: "v", format: "%v", err: notFound, exp: "fetch user 7: db overloaded"},
+		{name: "q", format: "%q", err: notFound, exp: `"fetch user 7: db overloaded"`},
+		{name: "+v", format: "%+v", err: notFound,
+			exp: "not_found: fetch user 7\n" +
+				"\thttp message: user not found\n" +
+				"\thttp details: map[id:7]\n" +
+				"caused by: retryable: db overloaded\n" +
+				"\tretry after: 2s"},
+		{name: "+v-no-data", format: "%+v", err: errors.WrapConflict(errDriver, nil),
+			exp: "conflict\ncaused by: driver: no rows in result set"},
+		{name: "+v-unknown", format: "%+v", err: errors.Error{Data: "legacy"},
+			exp: "unknown: legacy"},
+		{name: "width", format: "[%10s]", err: errors.NewNotFound("abc"), exp: "[       abc]"},
+		{name: "left-aligned", format: "[%-6v]", err: errors.NewNotFound("abc"), exp: "[abc   ]"},
+		{name: "precision", format: "[%.2s]", err: errors.NewNotFound("abc"), exp: "[ab]"},
+		{name: "hex", format: "%x", err: errors.NewNotFound("abc"), exp: "616263"},
+		{name: "#v", format: "%#v", err: errors.Error{IsClErr: true, Data: "abc"},
+			exp: `errors.Error{IsAuthErr:false, IsUnauthorizedErr:false, IsForbiddenErr:false, ` +
+				`IsClErr:true, IsNotFoundErr:false, IsNotImplementedErr:false, IsRetryableErr:false, ` +
+				`IsConflictErr:false, IsPreconditionFailedErr:false, Data:"abc", HttpMsg:"", Cause:error(nil), ` +
+				`HttpDetails:interface {}(nil), RetryAfter:0, kind:0, stack:(*errors.stack)(nil), fields:(*[]errors.Field)(nil)}`},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if out := fmt.Sprintf(tc.format, tc.err); out != tc.exp {
+				t.Errorf("expected:\n%s\ngot:\n%s", tc.exp, out)
+			}
+		})
+	}
+}
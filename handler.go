@@ -0,0 +1,100 @@
+package errors
+
+import (
+	"net/http"
+	"runtime/debug"
+)
+
+// HandlerFunc is like http.HandlerFunc but returns an error which, if not nil,
+// is written as the response. It implements http.Handler using the zero value
+// of Handler e.g:
+//
+//	http.Handle("/users", typederrs.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+//	    u, err := fetchUser(r)
+//	    if err != nil {
+//	        return err
+//	    }
+//	    return json.NewEncoder(w).Encode(u)
+//	}))
+//
+// Handlers should not write to w before returning an error, otherwise the
+// error response is appended to whatever they wrote.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// ServeHTTP calls f(w, r), writing the error returned (if any) the same way as
+// the zero value of Handler.
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Handler{Func: f}.ServeHTTP(w, r)
+}
+
+// Handler adapts a HandlerFunc into an http.Handler. Errors returned by Func
+// are written using ErrToHTTP.ToNegotiatedHTTPResponse. Errors that cannot be
+// written that way (i.e. have no class) and panics are written as a 500
+// Internal Server Error without exposing the details to the client.
+type Handler struct {
+	Func HandlerFunc
+	// Encoder is used when the request's Accept header matches none of
+	// HTTPEncoders. Defaults to DefaultHTTPEncoder if nil.
+	Encoder HTTPEncoder
+	// Strict, if true, only writes explicitly public messages as per
+	// ErrToHTTP.Strict.
+	Strict bool
+	// OnError, if set, is called with every error returned by Func (including
+	// recovered panics) and the status code it was written with e.g. for
+	// logging.
+	OnError func(r *http.Request, err error, status int)
+	// OnPanic, if set, is called with the value recovered from a panic in
+	// Func and the stack trace of the panicking goroutine. The panic is then
+	// handled as an error and hence also passed to OnError.
+	OnPanic func(r *http.Request, recovered interface{}, stack []byte)
+}
+
+// ServeHTTP calls h.Func(w, r), writing the error returned (if any) as the
+// response.
+func (h Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+		if recovered == http.ErrAbortHandler {
+			// Allows the handler to abort the response as per http.Handler.
+			panic(recovered)
+		}
+		if h.OnPanic != nil {
+			h.OnPanic(r, recovered, debug.Stack())
+		}
+		err, ok := recovered.(error)
+		if !ok {
+			err = Newf("%v", recovered)
+		}
+		// Whatever the class of a recovered error, a panic is a server error.
+		h.handled(r, Wrap(err, "panic"), h.writeInternal(w, r))
+	}()
+
+	err := h.Func(w, r)
+	if err == nil {
+		return
+	}
+	status, ok := ErrToHTTP{Encoder: h.Encoder, Strict: h.Strict}.ToNegotiatedHTTPResponse(err, w, r)
+	if !ok {
+		status = h.writeInternal(w, r)
+	}
+	h.handled(r, err, status)
+}
+
+// writeInternal writes a 500 Internal Server Error response that reveals
+// nothing about the error that caused it.
+func (h Handler) writeInternal(w http.ResponseWriter, r *http.Request) int {
+	status := http.StatusInternalServerError
+	msg := http.StatusText(status)
+	w.Header().Add("Vary", "Accept")
+	NegotiateHTTPEncoder(r, h.Encoder)(w, r, status, msg, New(msg))
+	return status
+}
+
+func (h Handler) handled(r *http.Request, err error, status int) {
+	if h.OnError != nil {
+		h.OnError(r, err, status)
+	}
+}
@@ -0,0 +1,143 @@
+package errors_test
+
+import (
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func TestHandler_ServeHTTP(t *testing.T) {
+	tt := []struct {
+		name      string
+		handle    errors.HandlerFunc
+		expStatus int
+		expBody   string
+		expErr    bool
+	}{
+		{
+			name: "success",
+			handle: func(w http.ResponseWriter, r *http.Request) error {
+				w.Write([]byte("ok"))
+				return nil
+			},
+			expStatus: http.StatusOK,
+			expBody:   "ok",
+		},
+		{
+			name: "typed-error",
+			handle: func(w http.ResponseWriter, r *http.Request) error {
+				return fmt.Errorf("load user: %w", errors.NewNotFoundWithHttp("user not found", "no rows"))
+			},
+			expStatus: http.StatusNotFound,
+			expBody:   "user not found\n",
+			expErr:    true,
+		},
+		{
+			name: "untyped-error",
+			handle: func(w http.ResponseWriter, r *http.Request) error {
+				return goerrors.New("pq: password authentication failed for user app")
+			},
+			expStatus: http.StatusInternalServerError,
+			expBody:   "Internal Server Error\n",
+			expErr:    true,
+		},
+		{
+			name: "panic",
+			handle: func(w http.ResponseWriter, r *http.Request) error {
+				var m map[string]int
+				m["boom"]++
+				return nil
+			},
+			expStatus: http.StatusInternalServerError,
+			expBody:   "Internal Server Error\n",
+			expErr:    true,
+		},
+		{
+			name: "panic-typed-error",
+			handle: func(w http.ResponseWriter, r *http.Request) error {
+				panic(errors.NewClient("secret details"))
+			},
+			expStatus: http.StatusInternalServerError,
+			expBody:   "Internal Server Error\n",
+			expErr:    true,
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotErr error
+			var gotStatus int
+			h := errors.Handler{
+				Func: tc.handle,
+				OnError: func(r *http.Request, err error, status int) {
+					gotErr, gotStatus = err, status
+				},
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+			if w.Code != tc.expStatus {
+				t.Errorf("expected status %d, got %d", tc.expStatus, w.Code)
+			}
+			if w.Body.String() != tc.expBody {
+				t.Errorf("expected body '%s', got '%s'", tc.expBody, w.Body.String())
+			}
+			if tc.expErr != (gotErr != nil) {
+				t.Fatalf("expected OnError called %t, got error %v", tc.expErr, gotErr)
+			}
+			if tc.expErr && gotStatus != tc.expStatus {
+				t.Errorf("expected OnError status %d, got %d", tc.expStatus, gotStatus)
+			}
+		})
+	}
+}
+
+func TestHandler_ServeHTTP_onPanic(t *testing.T) {
+	var recovered interface{}
+	var stack []byte
+	h := errors.Handler{
+		Func: func(w http.ResponseWriter, r *http.Request) error {
+			panic("boom")
+		},
+		OnPanic: func(r *http.Request, v interface{}, s []byte) {
+			recovered, stack = v, s
+		},
+	}
+	h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+	if recovered != "boom" {
+		t.Errorf("expected recovered value 'boom', got %v", recovered)
+	}
+	if !strings.Contains(string(stack), "TestHandler_ServeHTTP_onPanic") {
+		t.Errorf("expected the stack trace of the panic, got %s", stack)
+	}
+}
+
+func TestHandler_ServeHTTP_abortHandler(t *testing.T) {
+	defer func() {
+		if v := recover(); v != http.ErrAbortHandler {
+			t.Errorf("expected http.ErrAbortHandler to be re-panicked, got %v", v)
+		}
+	}()
+	errors.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		panic(http.ErrAbortHandler)
+	}).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+func TestHandlerFunc_ServeHTTP_negotiated(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	errors.HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return goerrors.New("untyped")
+	}).ServeHTTP(w, r)
+	if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("expected JSON Content-Type, got '%s'", ct)
+	}
+	exp := `{"error":{"class":"unknown","code":0,"message":"Internal Server Error"}}`
+	if w.Body.String() != exp {
+		t.Errorf("expected body '%s', got '%s'", exp, w.Body.String())
+	}
+}
@@ -0,0 +1,209 @@
+package errors
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// HTTPEncoder writes e to w as the body of an HTTP response with the
+// status code status. msg is the message meant for the client, as opposed to
+// e.Error() which may contain internal details. r is the request being
+// responded to, nil if not known.
+type HTTPEncoder func(w http.ResponseWriter, r *http.Request, status int, msg string, e Error)
+
+// DefaultHTTPEncoder writes the body of HTTP responses for which no
+// HTTPEncoder is specified, such as those written by Error.ToHTTPResponse
+// and the zero value of ErrToHTTP. It should only be changed during program
+// initialisation e.g:
+//
+//	func init() {
+//	    typederrs.DefaultHTTPEncoder = typederrs.EncodeJSON
+//	}
+var DefaultHTTPEncoder HTTPEncoder = EncodeText
+
+// StrictHTTPMessages, if true, keeps internal details out of HTTP responses by
+// only writing explicitly public messages (i.e. HttpMsg, as set by the
+// ...WithHttp functions). Errors without one are written with the generic
+// message of their Kind (see Kind.HTTPMessage) instead of Error(). It can also
+// be enabled per ErrToHTTP and Handler using their Strict fields. It should
+// only be changed during program initialisation.
+var StrictHTTPMessages = false
+
+// EncodeText writes msg as a text/plain body in the same way as http.Error.
+func EncodeText(w http.ResponseWriter, r *http.Request, status int, msg string, e Error) {
+	http.Error(w, msg, status)
+}
+
+// JSONBody is the body written by EncodeJSON e.g:
+//
+//	{"error":{"class":"not_found","code":2,"message":"user not found","fields":{"user_id":7}}}
+type JSONBody struct {
+	Error JSONError `json:"error"`
+}
+
+// JSONError describes an Error in a JSONBody.
+type JSONError struct {
+	// Class is the name of the Error's Kind.
+	Class string `json:"class"`
+	// Code is the numeric value of the Error's Kind.
+	Code int `json:"code"`
+	// Message is the message meant for the client.
+	Message string `json:"message"`
+	// Details is the Error's HttpDetails.
+	Details interface{} `json:"details,omitempty"`
+	// Fields are the public Fields of the Error's chain.
+	Fields map[string]interface{} `json:"fields,omitempty"`
+}
+
+// EncodeJSON writes e as an application/json JSONBody.
+func EncodeJSON(w http.ResponseWriter, r *http.Request, status int, msg string, e Error) {
+	k := e.Kind()
+	body := JSONBody{Error: JSONError{
+		Class:   k.String(),
+		Code:    int(k),
+		Message: msg,
+		Details: e.HttpDetails,
+		Fields:  publicFields(e),
+	}}
+	b, err := json.Marshal(body)
+	if err != nil {
+		// HttpDetails or Fields could not be marshalled, which should not
+		// prevent the client from receiving the rest of the error.
+		body.Error.Details, body.Error.Fields = nil, nil
+		b, _ = json.Marshal(body)
+	}
+	writeBody(w, "application/json; charset=utf-8", status, b)
+}
+
+// XMLError is the body written by EncodeXML e.g:
+//
+//	<error><class>not_found</class><code>2</code><message>user not found</message></error>
+//
+// Unlike JSONError, it does not carry the Error's HttpDetails or Fields as
+// those are not guaranteed to be representable in XML.
+type XMLError struct {
+	XMLName xml.Name `xml:"error"`
+	Class   string   `xml:"class"`
+	Code    int      `xml:"code"`
+	Message string   `xml:"message"`
+}
+
+// EncodeXML writes e as an application/xml XMLError.
+func EncodeXML(w http.ResponseWriter, r *http.Request, status int, msg string, e Error) {
+	k := e.Kind()
+	b, _ := xml.Marshal(XMLError{Class: k.String(), Code: int(k), Message: msg})
+	writeBody(w, "application/xml; charset=utf-8", status, append([]byte(xml.Header), b...))
+}
+
+// MediaTypeEncoder pairs an HTTPEncoder with the media type it writes.
+type MediaTypeEncoder struct {
+	MediaType string
+	Encoder   HTTPEncoder
+}
+
+// HTTPEncoders are the HTTPEncoders NegotiateHTTPEncoder picks from, in order of
+// preference for Accept header media ranges such as "application/*". It should
+// only be changed during program initialisation.
+var HTTPEncoders = []MediaTypeEncoder{
+	{MediaType: "text/plain", Encoder: EncodeText},
+	{MediaType: "application/json", Encoder: EncodeJSON},
+	{MediaType: "application/problem+json", Encoder: EncodeProblem},
+	{MediaType: "application/xml", Encoder: EncodeXML},
+	{MediaType: "text/xml", Encoder: EncodeXML},
+}
+
+// NegotiateHTTPEncoder returns the HTTPEncoder in HTTPEncoders that best matches
+// the Accept header of r. fallback is returned if r is nil, has no Accept
+// header, accepts any media type ("*/*") or accepts none of HTTPEncoders. A nil
+// fallback means DefaultHTTPEncoder.
+func NegotiateHTTPEncoder(r *http.Request, fallback HTTPEncoder) HTTPEncoder {
+	if fallback == nil {
+		fallback = DefaultHTTPEncoder
+	}
+	if r == nil {
+		return fallback
+	}
+	for _, mediaRange := range parseAccept(r.Header.Get("Accept")) {
+		if mediaRange == "*/*" {
+			return fallback
+		}
+		for _, me := range HTTPEncoders {
+			if mediaTypeMatches(mediaRange, me.MediaType) {
+				return me.Encoder
+			}
+		}
+	}
+	return fallback
+}
+
+// writeNegotiatedHTTP writes e as the response to r using the HTTPEncoder
+// negotiated from r's Accept header.
+func (e Error) writeNegotiatedHTTP(w http.ResponseWriter, r *http.Request, fallback HTTPEncoder, strict bool) (int, bool) {
+	if !e.hasHTTPStatus() {
+		return -1, false
+	}
+	w.Header().Add("Vary", "Accept")
+	return e.writeHTTP(w, r, NegotiateHTTPEncoder(r, fallback), strict)
+}
+
+// parseAccept returns the media ranges in an Accept header value ordered by
+// their quality value, highest first. Media ranges with a quality value of 0
+// (not acceptable) and those that cannot be parsed are left out.
+func parseAccept(accept string) []string {
+	type mediaRange struct {
+		mediaType string
+		q         float64
+	}
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qStr, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qStr, 64); err != nil {
+				continue
+			}
+		}
+		if q <= 0 {
+			continue
+		}
+		ranges = append(ranges, mediaRange{mediaType: mediaType, q: q})
+	}
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	mediaTypes := make([]string, len(ranges))
+	for i, mr := range ranges {
+		mediaTypes[i] = mr.mediaType
+	}
+	return mediaTypes
+}
+
+// mediaTypeMatches returns true if mediaType is within mediaRange
+// e.g. "application/json" is within "application/*".
+func mediaTypeMatches(mediaRange, mediaType string) bool {
+	if mediaRange == mediaType {
+		return true
+	}
+	rangeType, rangeSub, _ := strings.Cut(mediaRange, "/")
+	typ, _, _ := strings.Cut(mediaType, "/")
+	return rangeSub == "*" && rangeType == typ
+}
+
+// writeBody writes b as the body of the response with the headers set the same
+// way as http.Error.
+func writeBody(w http.ResponseWriter, contentType string, status int, b []byte) {
+	h := w.Header()
+	h.Del("Content-Length")
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	w.Write(b)
+}
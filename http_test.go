@@ -0,0 +1,172 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func TestEncodeJSON(t *testing.T) {
+	tt := []struct {
+		name       string
+		err        error
+		expStatus  int
+		expBody    errors.JSONBody
+		expDetails string
+	}{
+		{
+			name:      "http-message",
+			err:       errors.NewNotFoundWithHttp("user not found", "select * from users: no rows"),
+			expStatus: http.StatusNotFound,
+			expBody: errors.JSONBody{Error: errors.JSONError{
+				Class: "not_found", Code: int(errors.KindNotFound), Message: "user not found",
+			}},
+		},
+		{
+			name:      "wrapped-with-details",
+			err:       fmt.Errorf("handler: %w", errors.Error{IsClErr: true, Data: "bad", HttpDetails: map[string]string{"field": "email"}}),
+			expStatus: http.StatusBadRequest,
+			expBody: errors.JSONBody{Error: errors.JSONError{
+				Class: "client", Code: int(errors.KindClient), Message: "bad",
+			}},
+			expDetails: `{"field":"email"}`,
+		},
+		{
+			name:      "unmarshalable-details",
+			err:       errors.Error{IsConflictErr: true, Data: "dup", HttpDetails: func() {}},
+			expStatus: http.StatusConflict,
+			expBody: errors.JSONBody{Error: errors.JSONError{
+				Class: "conflict", Code: int(errors.KindConflict), Message: "dup",
+			}},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			status, ok := errors.ErrToHTTP{Encoder: errors.EncodeJSON}.ToHTTPResponse(tc.err, w)
+			if !ok || status != tc.expStatus || w.Code != tc.expStatus {
+				t.Fatalf("expected status %d, got (%d, %t) and response status %d",
+					tc.expStatus, status, ok, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+				t.Errorf("expected JSON Content-Type, got '%s'", ct)
+			}
+			if nosniff := w.Header().Get("X-Content-Type-Options"); nosniff != "nosniff" {
+				t.Errorf("expected X-Content-Type-Options nosniff, got '%s'", nosniff)
+			}
+			var body struct {
+				Error struct {
+					errors.JSONError
+					Details json.RawMessage `json:"details"`
+				} `json:"error"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("unmarshal body '%s': %v", w.Body.String(), err)
+			}
+			got := body.Error.JSONError
+			exp := tc.expBody.Error
+			if got.Class != exp.Class || got.Code != exp.Code || got.Message != exp.Message {
+				t.Errorf("expected %+v, got %+v", exp, got)
+			}
+			if string(body.Error.Details) != tc.expDetails {
+				t.Errorf("expected details '%s', got '%s'", tc.expDetails, body.Error.Details)
+			}
+		})
+	}
+}
+
+func TestError_ToHTTPResponse_defaultEncoder(t *testing.T) {
+	w := httptest.NewRecorder()
+	status, ok := errors.NewClientWithHttp("bad request", "parse body").ToHTTPResponse(w)
+	if !ok || status != http.StatusBadRequest {
+		t.Fatalf("expected (%d, true), got (%d, %t)", http.StatusBadRequest, status, ok)
+	}
+	if ct := w.Header().Get("Content-Type"); ct != "text/plain; charset=utf-8" {
+		t.Errorf("expected text Content-Type, got '%s'", ct)
+	}
+	if w.Body.String() != "bad request\n" {
+		t.Errorf("expected body 'bad request', got '%s'", w.Body.String())
+	}
+
+	w = httptest.NewRecorder()
+	errors.NewClient("bad").ToHTTPResponseWith(w, errors.EncodeJSON)
+	if ct := w.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+		t.Errorf("expected JSON Content-Type, got '%s'", ct)
+	}
+}
+
+func TestErrToHTTP_ToNegotiatedHTTPResponse(t *testing.T) {
+	tt := []struct {
+		name           string
+		accept         string
+		encoder        errors.HTTPEncoder
+		expContentType string
+	}{
+		{name: "no-accept", expContentType: "text/plain; charset=utf-8"},
+		{name: "any", accept: "*/*", expContentType: "text/plain; charset=utf-8"},
+		{name: "any-with-fallback", accept: "*/*", encoder: errors.EncodeJSON,
+			expContentType: "application/json; charset=utf-8"},
+		{name: "json", accept: "application/json", expContentType: "application/json; charset=utf-8"},
+		{name: "problem", accept: "application/problem+json, application/json;q=0.9",
+			expContentType: "application/problem+json"},
+		{name: "quality-order", accept: "application/json;q=0.5, application/xml",
+			expContentType: "application/xml; charset=utf-8"},
+		{name: "text-xml", accept: "text/xml", expContentType: "application/xml; charset=utf-8"},
+		{name: "subtype-wildcard", accept: "application/*", expContentType: "application/json; charset=utf-8"},
+		{name: "not-acceptable-skipped", accept: "application/json;q=0, text/plain;q=0.1",
+			expContentType: "text/plain; charset=utf-8"},
+		{name: "no-match", accept: "image/png", expContentType: "text/plain; charset=utf-8"},
+		{name: "no-match-with-fallback", accept: "image/png", encoder: errors.EncodeProblem,
+			expContentType: "application/problem+json"},
+		{name: "unparsable", accept: ";;;", expContentType: "text/plain; charset=utf-8"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+			if tc.accept != "" {
+				r.Header.Set("Accept", tc.accept)
+			}
+			w := httptest.NewRecorder()
+			err := fmt.Errorf("handler: %w", errors.NewNotFoundWithHttp("user not found", "no rows"))
+			status, ok := errors.ErrToHTTP{Encoder: tc.encoder}.ToNegotiatedHTTPResponse(err, w, r)
+			if !ok || status != http.StatusNotFound || w.Code != http.StatusNotFound {
+				t.Fatalf("expected status %d, got (%d, %t) and response status %d",
+					http.StatusNotFound, status, ok, w.Code)
+			}
+			if ct := w.Header().Get("Content-Type"); ct != tc.expContentType {
+				t.Errorf("expected Content-Type '%s', got '%s'", tc.expContentType, ct)
+			}
+			if vary := w.Header().Get("Vary"); vary != "Accept" {
+				t.Errorf("expected Vary 'Accept', got '%s'", vary)
+			}
+		})
+	}
+}
+
+func TestEncodeXML(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "application/xml")
+	w := httptest.NewRecorder()
+	errors.NewConflictWithHttp("already <exists>", "dup key").ToNegotiatedHTTPResponse(w, r)
+	exp := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<error><class>conflict</class><code>8</code><message>already &lt;exists&gt;</message></error>`
+	if w.Body.String() != exp {
+		t.Errorf("expected body '%s', got '%s'", exp, w.Body.String())
+	}
+}
+
+func TestErrToHTTP_ToNegotiatedHTTPResponse_untyped(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	status, ok := errors.ErrToHTTP{}.ToNegotiatedHTTPResponse(errors.New("untyped"), w, r)
+	if ok || status != -1 {
+		t.Errorf("expected (-1, false), got (%d, %t)", status, ok)
+	}
+	if len(w.Header()) != 0 {
+		t.Errorf("expected no headers to be written, got %v", w.Header())
+	}
+}
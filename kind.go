@@ -0,0 +1,415 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// Kind is the class of an Error e.g. KindNotFound. The numeric value of each
+// Kind is stable and can be relied upon as a machine readable error code.
+type Kind int
+
+const (
+	// KindUnknown is the Kind of an Error that has not been classified.
+	KindUnknown            Kind = 0
+	KindClient             Kind = 1
+	KindNotFound           Kind = 2
+	KindNotImplemented     Kind = 3
+	KindAuth               Kind = 4
+	KindUnauthorized       Kind = 5
+	KindForbidden          Kind = 6
+	KindRetryable          Kind = 7
+	KindConflict           Kind = 8
+	KindPreconditionFailed Kind = 9
+
+	// firstCustomKind is the lowest Kind available to RegisterKind. Lower
+	// values are reserved for the Kinds built into this package.
+	firstCustomKind Kind = 100
+)
+
+// kindInfo describes a Kind. A Kind belongs to its parent's class
+// e.g. KindForbidden is also a KindAuth.
+type kindInfo struct {
+	name        string
+	parent      Kind
+	httpStatus  int
+	httpMessage string
+	retryable   bool
+}
+
+// kinds holds the built-in and registered Kinds. It is guarded by kindsMu.
+var kindsMu sync.RWMutex
+var kinds = map[Kind]kindInfo{
+	KindUnknown: {name: "unknown", httpStatus: -1,
+		httpMessage: "internal error"},
+	KindClient: {name: "client", httpStatus: http.StatusBadRequest,
+		httpMessage: "bad request"},
+	KindNotFound: {name: "not_found", httpStatus: http.StatusNotFound,
+		httpMessage: "resource not found"},
+	KindNotImplemented: {name: "not_implemented", httpStatus: http.StatusNotImplemented,
+		httpMessage: "not implemented"},
+	KindAuth: {name: "auth", httpStatus: http.StatusUnauthorized,
+		httpMessage: "authentication required"},
+	KindUnauthorized: {name: "unauthorized", parent: KindAuth, httpStatus: http.StatusUnauthorized,
+		httpMessage: "authentication required"},
+	KindForbidden: {name: "forbidden", parent: KindAuth, httpStatus: http.StatusForbidden,
+		httpMessage: "access denied"},
+	KindRetryable: {name: "retryable", httpStatus: http.StatusServiceUnavailable, retryable: true,
+		httpMessage: "service unavailable"},
+	KindConflict: {name: "conflict", httpStatus: http.StatusConflict,
+		httpMessage: "resource conflict"},
+	KindPreconditionFailed: {name: "precondition_failed", httpStatus: http.StatusPreconditionFailed,
+		httpMessage: "precondition failed"},
+}
+
+// legacyKindPrecedence lists, in order of precedence, the Kinds that the
+// boolean flags of Error (IsForbiddenErr, IsClErr...) resolve to.
+var legacyKindPrecedence = []Kind{
+	KindForbidden,
+	KindUnauthorized,
+	KindAuth,
+	KindClient,
+	KindNotFound,
+	KindNotImplemented,
+	KindRetryable,
+	KindConflict,
+	KindPreconditionFailed,
+}
+
+// KindSpec describes a custom Kind to RegisterKind.
+type KindSpec struct {
+	// Name is the unique name of the Kind as returned by Kind.String().
+	Name string
+	// Code is the numeric value of the Kind. It must be unique and no less
+	// than 100, the values below that being reserved for this package.
+	// A zero Code assigns the next available value, which is only stable
+	// if Kinds are always registered in the same order.
+	Code Kind
+	// Parent, if set, is the Kind whose class the Kind also belongs to
+	// e.g. KindClient for a QuotaExceeded Kind.
+	Parent Kind
+	// HTTPStatus is the status code an Error of the Kind is written with.
+	// Zero means use that of Parent; ToHTTPResponse does not write Errors
+	// of a Kind that ends up without a status code.
+	HTTPStatus int
+	// Retryable marks Errors of the Kind as retryable. Kinds that descend
+	// from a retryable Kind are retryable regardless.
+	Retryable bool
+	// HTTPMessage is the generic message written for Errors of the Kind
+	// that have no HttpMsg when StrictHTTPMessages is set. Empty means use
+	// that of Parent, or the lower-cased status text of HTTPStatus if the
+	// Kind has no Parent.
+	HTTPMessage string
+}
+
+// RegisterKind adds a custom Kind to those built into this package e.g:
+//
+//	var KindQuotaExceeded = typederrs.MustRegisterKind(typederrs.KindSpec{
+//	    Name:       "quota_exceeded",
+//	    Parent:     typederrs.KindClient,
+//	    HTTPStatus: http.StatusTooManyRequests,
+//	})
+//
+// Errors of the Kind are created using its methods (Kind.New, Kind.Wrap...)
+// and are treated the same way as the built-in Kinds by the checkers,
+// errors.Is (see Kind.Err) and ToHTTPResponse.
+// RegisterKind is meant to be called during program initialisation.
+func RegisterKind(spec KindSpec) (Kind, error) {
+
+	if spec.Name == "" {
+		return KindUnknown, New("kind name is required")
+	}
+	if spec.HTTPStatus != 0 && (spec.HTTPStatus < 100 || spec.HTTPStatus > 599) {
+		return KindUnknown, Newf("invalid HTTP status %d for kind %s",
+			spec.HTTPStatus, spec.Name)
+	}
+	if spec.Code != 0 && spec.Code < firstCustomKind {
+		return KindUnknown, Newf("kind code %d for %s is reserved, use %d or greater",
+			spec.Code, spec.Name, firstCustomKind)
+	}
+
+	kindsMu.Lock()
+	defer kindsMu.Unlock()
+
+	if _, exists := kindByName(spec.Name); exists {
+		return KindUnknown, Newf("kind %s already registered", spec.Name)
+	}
+	if _, exists := kinds[spec.Code]; exists && spec.Code != 0 {
+		return KindUnknown, Newf("kind code %d for %s already registered",
+			spec.Code, spec.Name)
+	}
+	parent, exists := kinds[spec.Parent]
+	if !exists {
+		return KindUnknown, Newf("parent kind %d for %s is not registered",
+			spec.Parent, spec.Name)
+	}
+
+	k := spec.Code
+	if k == 0 {
+		k = firstCustomKind
+		for existing := range kinds {
+			if existing >= k {
+				k = existing + 1
+			}
+		}
+	}
+
+	status := spec.HTTPStatus
+	if status == 0 {
+		status = parent.httpStatus
+	}
+
+	msg := spec.HTTPMessage
+	if msg == "" {
+		msg = parent.httpMessage
+		if text := http.StatusText(status); spec.Parent == KindUnknown && text != "" {
+			msg = strings.ToLower(text)
+		}
+	}
+
+	kinds[k] = kindInfo{
+		name:        spec.Name,
+		parent:      spec.Parent,
+		httpStatus:  status,
+		httpMessage: msg,
+		retryable:   spec.Retryable,
+	}
+	return k, nil
+}
+
+// MustRegisterKind is like RegisterKind but panics if the Kind cannot be
+// registered.
+func MustRegisterKind(spec KindSpec) Kind {
+	k, err := RegisterKind(spec)
+	if err != nil {
+		panic(err)
+	}
+	return k
+}
+
+// KindByName returns the Kind whose String() is name.
+func KindByName(name string) (Kind, bool) {
+	kindsMu.RLock()
+	defer kindsMu.RUnlock()
+	return kindByName(name)
+}
+
+// kindByName is KindByName for callers that hold kindsMu.
+func kindByName(name string) (Kind, bool) {
+	for k, info := range kinds {
+		if info.name == name {
+			return k, true
+		}
+	}
+	return KindUnknown, false
+}
+
+// kindByHTTPStatus returns the registered (i.e. not built-in) Kind with
+// the lowest value whose HTTP status code is status.
+func kindByHTTPStatus(status int) (Kind, bool) {
+	kindsMu.RLock()
+	defer kindsMu.RUnlock()
+	found := KindUnknown
+	for k, info := range kinds {
+		if k >= firstCustomKind && info.httpStatus == status && (found == KindUnknown || k < found) {
+			found = k
+		}
+	}
+	return found, found != KindUnknown
+}
+
+func lookupKind(k Kind) (kindInfo, bool) {
+	kindsMu.RLock()
+	defer kindsMu.RUnlock()
+	info, ok := kinds[k]
+	return info, ok
+}
+
+// String returns the name of the Kind e.g. "not_found".
+func (k Kind) String() string {
+	if info, ok := lookupKind(k); ok {
+		return info.name
+	}
+	return fmt.Sprintf("Kind(%d)", int(k))
+}
+
+// Parent returns the Kind whose class k also belongs to e.g. KindAuth for
+// KindForbidden. Returns KindUnknown if k has no parent.
+func (k Kind) Parent() Kind {
+	info, _ := lookupKind(k)
+	return info.parent
+}
+
+// Is returns true if k is target or descends from target. As retryability is
+// a trait that can be set on any registered Kind, k is also a KindRetryable
+// if it is retryable (see Kind.Retryable).
+func (k Kind) Is(target Kind) bool {
+	if target == KindRetryable && k.Retryable() {
+		return true
+	}
+	if k == target {
+		return true
+	}
+	for p := k.Parent(); p != KindUnknown; p = p.Parent() {
+		if p == target {
+			return true
+		}
+	}
+	return false
+}
+
+// Retryable returns true if Errors of Kind k are not permanent and should be
+// retried i.e. k or one of its ancestors is retryable.
+func (k Kind) Retryable() bool {
+	for ; k != KindUnknown; k = k.Parent() {
+		if info, _ := lookupKind(k); info.retryable {
+			return true
+		}
+	}
+	return false
+}
+
+// HTTPStatus returns the HTTP status code an Error of Kind k is written with,
+// -1 if k does not map to an HTTP status code.
+func (k Kind) HTTPStatus() int {
+	if info, ok := lookupKind(k); ok {
+		return info.httpStatus
+	}
+	return -1
+}
+
+// HTTPMessage returns the generic message written for Errors of Kind k that
+// have no HttpMsg when StrictHTTPMessages is set e.g. "resource not found" for
+// KindNotFound. Unknown Kinds get that of KindUnknown, "internal error".
+func (k Kind) HTTPMessage() string {
+	if info, ok := lookupKind(k); ok {
+		return info.httpMessage
+	}
+	return KindUnknown.HTTPMessage()
+}
+
+// Err returns the sentinel of Kind k for use with errors.Is e.g.
+// errors.Is(err, KindQuotaExceeded.Err()). The built-in Kinds' sentinels
+// are also available as ErrClient, ErrNotFound...
+func (k Kind) Err() error {
+	return kindErr(k)
+}
+
+// New creates a new error of Kind k.
+func (k Kind) New(data interface{}) Error {
+	return newError(k, nil, "", data)
+}
+
+// Newf creates a new error of Kind k with fmt.Printf style formatting.
+func (k Kind) Newf(format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return k.New(data)
+}
+
+// NewWithHttp creates a new error of Kind k containing a http specific
+// error message.
+func (k Kind) NewWithHttp(httpMsg string, data interface{}) Error {
+	return newError(k, nil, httpMsg, data)
+}
+
+// NewWithHttpf creates a new error of Kind k containing a http specific
+// error message.
+func (k Kind) NewWithHttpf(httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return k.NewWithHttp(httpMsg, data)
+}
+
+// Wrap creates an error of Kind k with err as its Cause.
+func (k Kind) Wrap(err error, data interface{}) Error {
+	return newError(k, err, "", data)
+}
+
+// Wrapf creates an error of Kind k with err as its Cause and fmt.Printf
+// style formatting.
+func (k Kind) Wrapf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return k.Wrap(err, data)
+}
+
+// WrapWithHttp creates an error of Kind k with err as its Cause containing
+// a http specific error message.
+func (k Kind) WrapWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(k, err, httpMsg, data)
+}
+
+// WrapWithHttpf creates an error of Kind k with err as its Cause containing
+// a http specific error message.
+func (k Kind) WrapWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return k.WrapWithHttp(err, httpMsg, data)
+}
+
+// newError creates an Error of Kind k. The legacy flag(s) matching k are also
+// set for the benefit of code that reads them directly, and the stack trace
+// captured if enabled.
+func newError(k Kind, cause error, httpMsg string, data interface{}) Error {
+	e := Error{kind: k, Cause: cause, HttpMsg: httpMsg, Data: data, stack: callers()}
+	switch k {
+	case KindClient:
+		e.IsClErr = true
+	case KindNotFound:
+		e.IsNotFoundErr = true
+	case KindNotImplemented:
+		e.IsNotImplementedErr = true
+	case KindAuth:
+		e.IsAuthErr = true
+	case KindUnauthorized:
+		e.IsAuthErr, e.IsUnauthorizedErr = true, true
+	case KindForbidden:
+		e.IsAuthErr, e.IsForbiddenErr = true, true
+	case KindRetryable:
+		e.IsRetryableErr = true
+	case KindConflict:
+		e.IsConflictErr = true
+	case KindPreconditionFailed:
+		e.IsPreconditionFailedErr = true
+	}
+	return e
+}
+
+// legacyFlag returns the value of e's boolean flag matching k.
+func (e Error) legacyFlag(k Kind) bool {
+	switch k {
+	case KindClient:
+		return e.IsClErr
+	case KindNotFound:
+		return e.IsNotFoundErr
+	case KindNotImplemented:
+		return e.IsNotImplementedErr
+	case KindAuth:
+		return e.IsAuthErr
+	case KindUnauthorized:
+		return e.IsUnauthorizedErr
+	case KindForbidden:
+		return e.IsForbiddenErr
+	case KindRetryable:
+		return e.IsRetryableErr
+	case KindConflict:
+		return e.IsConflictErr
+	case KindPreconditionFailed:
+		return e.IsPreconditionFailedErr
+	}
+	return false
+}
+
+// hasKind returns true if e belongs to the class of Kind k. Besides e.Kind(),
+// every legacy flag set on e is considered, such that an Error with both
+// IsClErr and IsNotFoundErr set is both a client and a not found error.
+func (e Error) hasKind(k Kind) bool {
+	if e.kind != KindUnknown && e.kind.Is(k) {
+		return true
+	}
+	for _, lk := range legacyKindPrecedence {
+		if e.legacyFlag(lk) && lk.Is(k) {
+			return true
+		}
+	}
+	return false
+}
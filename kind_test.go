@@ -0,0 +1,213 @@
+package errors_test
+
+import (
+	goerrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func TestError_Kind(t *testing.T) {
+	tt := []struct {
+		name    string
+		err     errors.Error
+		expKind errors.Kind
+	}{
+		{name: "new", err: errors.New("plain"), expKind: errors.KindUnknown},
+		{name: "client", err: errors.NewClient("bad"), expKind: errors.KindClient},
+		{name: "not-found", err: errors.NewNotFoundf("no %s", "user"), expKind: errors.KindNotFound},
+		{name: "not-implemented", err: errors.NewNotImplemented(), expKind: errors.KindNotImplemented},
+		{name: "auth", err: errors.NewAuth("who"), expKind: errors.KindAuth},
+		{name: "unauthorized", err: errors.NewUnauthorized("who"), expKind: errors.KindUnauthorized},
+		{name: "forbidden", err: errors.NewForbiddentWithHttp("nope", "nope"), expKind: errors.KindForbidden},
+		{name: "retryable", err: errors.NewRetryable("later"), expKind: errors.KindRetryable},
+		{name: "conflict", err: errors.NewConflict("dup"), expKind: errors.KindConflict},
+		{name: "precondition-failed", err: errors.NewPreconditionFailed("stale"),
+			expKind: errors.KindPreconditionFailed},
+		{name: "wrapped", err: errors.WrapConflict(errDriver, nil), expKind: errors.KindConflict},
+		{name: "legacy-single-flag", err: errors.Error{IsNotFoundErr: true}, expKind: errors.KindNotFound},
+		{name: "legacy-client-and-not-found", err: errors.Error{IsClErr: true, IsNotFoundErr: true},
+			expKind: errors.KindClient},
+		{name: "legacy-all-flags", err: errWithAllFlagsTrue, expKind: errors.KindForbidden},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if k := tc.err.Kind(); k != tc.expKind {
+				t.Errorf("expected Kind %v, got %v", tc.expKind, k)
+			}
+		})
+	}
+}
+
+func TestNewForbidden_legacyFlags(t *testing.T) {
+	err := errors.NewForbidden("nope")
+	if !err.IsAuthErr || !err.IsForbiddenErr {
+		t.Errorf("expected IsAuthErr and IsForbiddenErr to be set on %+v", err)
+	}
+	if err.IsClErr || err.IsUnauthorizedErr {
+		t.Errorf("expected no other flags to be set on %+v", err)
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	tt := []struct {
+		kind   errors.Kind
+		expStr string
+	}{
+		{kind: errors.KindUnknown, expStr: "unknown"},
+		{kind: errors.KindNotFound, expStr: "not_found"},
+		{kind: errors.KindPreconditionFailed, expStr: "precondition_failed"},
+		{kind: errors.Kind(4242), expStr: "Kind(4242)"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.expStr, func(t *testing.T) {
+			if s := tc.kind.String(); s != tc.expStr {
+				t.Errorf("expected '%s', got '%s'", tc.expStr, s)
+			}
+		})
+	}
+}
+
+func TestKind_Is(t *testing.T) {
+	if !errors.KindForbidden.Is(errors.KindAuth) {
+		t.Errorf("expected KindForbidden to be a KindAuth")
+	}
+	if !errors.KindUnauthorized.Is(errors.KindAuth) {
+		t.Errorf("expected KindUnauthorized to be a KindAuth")
+	}
+	if errors.KindAuth.Is(errors.KindForbidden) {
+		t.Errorf("expected KindAuth not to be a KindForbidden")
+	}
+	if errors.KindNotFound.Is(errors.KindUnknown) {
+		t.Errorf("expected KindNotFound not to be a KindUnknown")
+	}
+}
+
+func TestKind_HTTPStatus(t *testing.T) {
+	tt := []struct {
+		kind      errors.Kind
+		expStatus int
+	}{
+		{kind: errors.KindUnknown, expStatus: -1},
+		{kind: errors.KindClient, expStatus: http.StatusBadRequest},
+		{kind: errors.KindAuth, expStatus: http.StatusUnauthorized},
+		{kind: errors.KindForbidden, expStatus: http.StatusForbidden},
+		{kind: errors.KindRetryable, expStatus: http.StatusServiceUnavailable},
+		{kind: errors.Kind(4242), expStatus: -1},
+	}
+	for _, tc := range tt {
+		t.Run(tc.kind.String(), func(t *testing.T) {
+			if s := tc.kind.HTTPStatus(); s != tc.expStatus {
+				t.Errorf("expected %d, got %d", tc.expStatus, s)
+			}
+		})
+	}
+}
+
+var (
+	kindQuotaExceeded = errors.MustRegisterKind(errors.KindSpec{
+		Name:       "test_quota_exceeded",
+		Parent:     errors.KindClient,
+		HTTPStatus: http.StatusTooManyRequests,
+	})
+	kindGone = errors.MustRegisterKind(errors.KindSpec{
+		Name:   "test_gone",
+		Code:   4100,
+		Parent: errors.KindNotFound,
+	})
+	kindLocked = errors.MustRegisterKind(errors.KindSpec{
+		Name:       "test_locked",
+		HTTPStatus: http.StatusLocked,
+		Retryable:  true,
+	})
+)
+
+func TestRegisterKind_customKinds(t *testing.T) {
+	checker := errors.AllErrCheck{}
+
+	quotaErr := kindQuotaExceeded.Newf("quota of %d exceeded", 10)
+	if quotaErr.Kind() != kindQuotaExceeded {
+		t.Errorf("expected Kind %v, got %v", kindQuotaExceeded, quotaErr.Kind())
+	}
+	if !checker.IsClientError(quotaErr) || !checker.IsKindError(quotaErr, kindQuotaExceeded) {
+		t.Errorf("expected IsClientError and IsKindError true for %v", quotaErr)
+	}
+	if !goerrors.Is(quotaErr, kindQuotaExceeded.Err()) || !goerrors.Is(quotaErr, errors.ErrClient) {
+		t.Errorf("expected %v to match both its own and its parent's sentinel", quotaErr)
+	}
+	if checker.IsRetryableError(quotaErr) || checker.IsKindError(quotaErr, kindGone) {
+		t.Errorf("expected IsRetryableError and IsKindError(gone) false for %v", quotaErr)
+	}
+	if quotaErr.Error() != "quota of 10 exceeded" {
+		t.Errorf("expected error message '%s', got '%s'", "quota of 10 exceeded", quotaErr.Error())
+	}
+
+	goneErr := kindGone.Wrap(errDriver, "fetch order")
+	if kindGone != 4100 || kindGone.String() != "test_gone" {
+		t.Errorf("expected kind 4100 named test_gone, got %d named %s", kindGone, kindGone)
+	}
+	if !checker.IsNotFoundError(goneErr) || !goerrors.Is(goneErr, errDriver) {
+		t.Errorf("expected a not found error wrapping the driver error, got %v", goneErr)
+	}
+
+	lockedErr := kindLocked.New("row locked")
+	if !checker.IsRetryableError(lockedErr) || !goerrors.Is(lockedErr, errors.ErrRetryable) {
+		t.Errorf("expected IsRetryableError true for %v", lockedErr)
+	}
+
+	tt := []struct {
+		name      string
+		err       errors.Error
+		expStatus int
+	}{
+		{name: "own-status", err: quotaErr, expStatus: http.StatusTooManyRequests},
+		{name: "parent-status", err: goneErr, expStatus: http.StatusNotFound},
+		{name: "retryable-own-status", err: lockedErr, expStatus: http.StatusLocked},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			status, ok := errors.ErrToHTTP{}.ToHTTPResponse(tc.err, w)
+			if !ok || status != tc.expStatus || w.Code != tc.expStatus {
+				t.Errorf("expected status %d, got (%d, %t) and response status %d",
+					tc.expStatus, status, ok, w.Code)
+			}
+		})
+	}
+}
+
+func TestRegisterKind_invalidSpec(t *testing.T) {
+	tt := []struct {
+		name string
+		spec errors.KindSpec
+	}{
+		{name: "no-name", spec: errors.KindSpec{}},
+		{name: "duplicate-name", spec: errors.KindSpec{Name: "test_gone"}},
+		{name: "builtin-name", spec: errors.KindSpec{Name: "not_found"}},
+		{name: "duplicate-code", spec: errors.KindSpec{Name: "test_dup_code", Code: 4100}},
+		{name: "reserved-code", spec: errors.KindSpec{Name: "test_reserved", Code: 42}},
+		{name: "unknown-parent", spec: errors.KindSpec{Name: "test_orphan", Parent: 9999}},
+		{name: "invalid-status", spec: errors.KindSpec{Name: "test_bad_status", HTTPStatus: 42}},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			if k, err := errors.RegisterKind(tc.spec); err == nil {
+				t.Errorf("expected an error, got kind %v", k)
+			}
+		})
+	}
+}
+
+func TestKindByName(t *testing.T) {
+	if k, ok := errors.KindByName("test_quota_exceeded"); !ok || k != kindQuotaExceeded {
+		t.Errorf("expected (%v, true), got (%v, %t)", kindQuotaExceeded, k, ok)
+	}
+	if k, ok := errors.KindByName("conflict"); !ok || k != errors.KindConflict {
+		t.Errorf("expected (%v, true), got (%v, %t)", errors.KindConflict, k, ok)
+	}
+	if _, ok := errors.KindByName("no_such_kind"); ok {
+		t.Errorf("expected no kind named no_such_kind")
+	}
+}
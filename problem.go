@@ -0,0 +1,114 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemTypeBase is prefixed to the name of an Error's Kind to form the type
+// member of the problem details written by EncodeProblem
+// e.g. "urn:go-typed-errors:problem:not_found". Errors of KindUnknown get the
+// RFC 9457 default of "about:blank". It should only be changed during program
+// initialisation.
+var ProblemTypeBase = "urn:go-typed-errors:problem:"
+
+// Problem is an RFC 9457 (formerly RFC 7807) problem details object.
+type Problem struct {
+	Type     string `json:"type,omitempty"`
+	Title    string `json:"title,omitempty"`
+	Status   int    `json:"status,omitempty"`
+	Detail   string `json:"detail,omitempty"`
+	Instance string `json:"instance,omitempty"`
+	// Extensions are additional members of the problem details object.
+	// They never replace the members above.
+	Extensions map[string]json.RawMessage `json:"-"`
+}
+
+// MarshalJSON marshals p as a single object containing both the standard
+// members and the Extensions.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	type problem Problem
+	b, err := json.Marshal(problem(p))
+	if err != nil || len(p.Extensions) == 0 {
+		return b, err
+	}
+	members := make(map[string]json.RawMessage, len(p.Extensions)+5)
+	for k, v := range p.Extensions {
+		members[k] = v
+	}
+	if err := json.Unmarshal(b, &members); err != nil {
+		return nil, err
+	}
+	return json.Marshal(members)
+}
+
+// ProblemType returns the type member of the problem details for Errors of
+// Kind k.
+func ProblemType(k Kind) string {
+	if k == KindUnknown {
+		return "about:blank"
+	}
+	return ProblemTypeBase + k.String()
+}
+
+// EncodeProblem writes e as an application/problem+json body. The detail member
+// is msg, instance is the URI of r (if known) and the public Fields of the
+// Error's chain and its HttpDetails become extension members. HttpDetails
+// become each member if they marshal into a JSON object, a single "details"
+// member otherwise, and take precedence over Fields with the same key.
+func EncodeProblem(w http.ResponseWriter, r *http.Request, status int, msg string, e Error) {
+	p := Problem{
+		Type:   ProblemType(e.Kind()),
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: msg,
+	}
+	if r != nil && r.URL != nil {
+		p.Instance = r.URL.RequestURI()
+	}
+	p.Extensions = problemExtensions(e)
+	b, err := json.Marshal(p)
+	if err != nil {
+		p.Extensions = nil
+		b, _ = json.Marshal(p)
+	}
+	writeBody(w, "application/problem+json", status, b)
+}
+
+// problemExtensions returns the extension members derived from e's public
+// Fields and HttpDetails, nil if there are none. Those that cannot be
+// marshalled are left out.
+func problemExtensions(e Error) map[string]json.RawMessage {
+	var members map[string]json.RawMessage
+	add := func(k string, v json.RawMessage) {
+		if members == nil {
+			members = make(map[string]json.RawMessage)
+		}
+		members[k] = v
+	}
+	for k, v := range publicFields(e) {
+		if b, err := json.Marshal(v); err == nil {
+			add(k, b)
+		}
+	}
+	if e.HttpDetails == nil {
+		return members
+	}
+	b, err := json.Marshal(e.HttpDetails)
+	if err != nil {
+		return members
+	}
+	if !bytes.HasPrefix(b, []byte("{")) {
+		add("details", b)
+		return members
+	}
+	var details map[string]json.RawMessage
+	if err := json.Unmarshal(b, &details); err != nil {
+		return members
+	}
+	for k, v := range details {
+		add(k, v)
+	}
+	return members
+}
@@ -0,0 +1,115 @@
+package errors_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func TestEncodeProblem(t *testing.T) {
+	tt := []struct {
+		name    string
+		err     errors.Error
+		r       *http.Request
+		expBody map[string]interface{}
+	}{
+		{
+			name: "standard-members",
+			err:  errors.NewNotFoundWithHttp("no user with that ID", "select users: no rows"),
+			r:    httptest.NewRequest(http.MethodGet, "/users/42?expand=true", nil),
+			expBody: map[string]interface{}{
+				"type":     "urn:go-typed-errors:problem:not_found",
+				"title":    "Not Found",
+				"status":   float64(http.StatusNotFound),
+				"detail":   "no user with that ID",
+				"instance": "/users/42?expand=true",
+			},
+		},
+		{
+			name: "object-extensions",
+			err: errors.Error{IsConflictErr: true, Data: "dup", HttpDetails: map[string]interface{}{
+				"conflicting_id": "u-1",
+				"status":         "cannot override",
+			}},
+			expBody: map[string]interface{}{
+				"type":           "urn:go-typed-errors:problem:conflict",
+				"title":          "Conflict",
+				"status":         float64(http.StatusConflict),
+				"detail":         "dup",
+				"conflicting_id": "u-1",
+			},
+		},
+		{
+			name: "non-object-extension",
+			err:  errors.Error{IsClErr: true, Data: "bad", HttpDetails: []string{"email", "name"}},
+			expBody: map[string]interface{}{
+				"type":    "urn:go-typed-errors:problem:client",
+				"title":   "Bad Request",
+				"status":  float64(http.StatusBadRequest),
+				"detail":  "bad",
+				"details": []interface{}{"email", "name"},
+			},
+		},
+		{
+			name: "public-fields",
+			err: errors.WrapClient(errors.NewNotFound("x").WithPublicField("order_id", 7), "bad").
+				WithPublicField("user_id", 3).
+				WithField("table", "orders").
+				WithFields(errors.NewPublicField("email", "x@y")),
+			expBody: map[string]interface{}{
+				"type":     "urn:go-typed-errors:problem:client",
+				"title":    "Bad Request",
+				"status":   float64(http.StatusBadRequest),
+				"detail":   "bad: x",
+				"user_id":  3,
+				"order_id": 7,
+				"email":    "x@y",
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			msg := tc.err.HttpMsg
+			if msg == "" {
+				msg = tc.err.Error()
+			}
+			errors.EncodeProblem(w, tc.r, tc.err.Kind().HTTPStatus(), msg, tc.err)
+			if ct := w.Header().Get("Content-Type"); ct != "application/problem+json" {
+				t.Errorf("expected problem+json Content-Type, got '%s'", ct)
+			}
+			var body map[string]interface{}
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("unmarshal body '%s': %v", w.Body.String(), err)
+			}
+			if len(body) != len(tc.expBody) {
+				t.Errorf("expected %d members, got %d: %s", len(tc.expBody), len(body), w.Body.String())
+			}
+			for k, v := range tc.expBody {
+				if got, _ := json.Marshal(body[k]); string(got) != mustMarshal(t, v) {
+					t.Errorf("expected member %s to be %s, got %s", k, mustMarshal(t, v), got)
+				}
+			}
+		})
+	}
+}
+
+func TestProblemType(t *testing.T) {
+	if pt := errors.ProblemType(errors.KindUnknown); pt != "about:blank" {
+		t.Errorf("expected about:blank, got '%s'", pt)
+	}
+	if pt := errors.ProblemType(kindQuotaExceeded); pt != "urn:go-typed-errors:problem:test_quota_exceeded" {
+		t.Errorf("expected custom kind type URI, got '%s'", pt)
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("marshal %v: %v", v, err)
+	}
+	return string(b)
+}
@@ -1,14 +1,84 @@
 package errors
 
 import (
+	"context"
+	goerrors "errors"
+	"fmt"
 	"time"
-	"github.com/jpillora/backoff"
 )
 
+// ErrRetriesExhausted is matched using errors.Is by the RetriesExhaustedError
+// returned by DoWithRetries.
+var ErrRetriesExhausted = goerrors.New("retries exhausted")
+
+// ErrMaxElapsedTime is the Reason of a RetriesExhaustedError returned because
+// waiting for another attempt would have exceeded the time allowed by
+// RetryWithMaxElapsedTime.
+var ErrMaxElapsedTime = goerrors.New("retry time budget exceeded")
+
+// ErrRetryAfterTooLong is the Reason of a RetriesExhaustedError returned
+// because the error of an attempt asked to wait longer before retrying (see
+// RetryAfter) than allowed by RetryWithMaxBackoff.
+var ErrRetryAfterTooLong = goerrors.New("requested retry delay exceeds maximum backoff")
+
+// RetriesExhaustedError is returned by DoWithRetries when every attempt allowed
+// failed with a retryable error. It wraps the error of every attempt, most
+// recent first, such that the checkers and ErrToHTTP classify it the same way
+// as the error of the last attempt.
+type RetriesExhaustedError struct {
+	// Attempts holds the error returned by each attempt in the order
+	// the attempts were made.
+	Attempts []error
+	// Reason is why no more attempts were made: nil if the number of
+	// attempts allowed by RetryWithMaxRetries was reached, ErrMaxElapsedTime
+	// if the time allowed by RetryWithMaxElapsedTime was,
+	// ErrRetryBudgetExhausted if the RetryBudget allowed no more retries or
+	// ErrRetryAfterTooLong if the delay requested by the last attempt's error
+	// was longer than allowed by RetryWithMaxBackoff.
+	Reason error
+}
+
+// Error returns the error message of the last attempt's error prefixed with
+// the Reason, "too many retries" if there is none.
+func (e *RetriesExhaustedError) Error() string {
+	if e.Reason != nil {
+		return fmt.Sprintf("%v: %v", e.Reason, e.Last())
+	}
+	return fmt.Sprintf("too many retries: %v", e.Last())
+}
+
+// Last returns the error of the last attempt.
+func (e *RetriesExhaustedError) Last() error {
+	if len(e.Attempts) == 0 {
+		return nil
+	}
+	return e.Attempts[len(e.Attempts)-1]
+}
+
+// Unwrap returns the errors of the attempts, most recent first.
+func (e *RetriesExhaustedError) Unwrap() []error {
+	errs := make([]error, len(e.Attempts))
+	for i, err := range e.Attempts {
+		errs[len(errs)-1-i] = err
+	}
+	return errs
+}
+
+// Is returns true if target is ErrRetriesExhausted or the Reason.
+func (e *RetriesExhaustedError) Is(target error) bool {
+	return target == ErrRetriesExhausted || (e.Reason != nil && target == e.Reason)
+}
+
 type RetryConfig struct {
-	backoff *backoff.Backoff
-	checker IsRetryableErrChecker
+	backoff    ExponentialBackoff
+	strategy   BackoffStrategy
+	checker    IsRetryableErrChecker
 	maxRetries int
+	onRetry    func(attempt int, err error, nextDelay time.Duration)
+	onGiveUp   func(attempts int, err error)
+	clock      Clock
+	maxElapsed time.Duration
+	budget     *RetryBudget
 }
 
 type RetryOption func(*RetryConfig)
@@ -19,6 +89,11 @@ func RetryWithMinBackoff(d time.Duration) RetryOption {
 	}
 }
 
+// RetryWithMaxBackoff sets the maximum wait between attempts, 5 minutes by
+// default. It also applies to the delay requested by the error of an attempt
+// (see RetryAfter): rather than waiting less than requested, and most likely
+// failing again, retrying stops with a *RetriesExhaustedError whose Reason is
+// ErrRetryAfterTooLong. A d of 0 or less means such delays are not limited.
 func RetryWithMaxBackoff(d time.Duration) RetryOption {
 	return func(b *RetryConfig) {
 		b.backoff.Max = d
@@ -37,6 +112,18 @@ func RetryWithBackoffJitter(t bool) RetryOption {
 	}
 }
 
+// RetryWithBackoffStrategy sets the BackoffStrategy that determines how long to
+// wait between attempts. By default, an ExponentialBackoff configured using
+// RetryWithMinBackoff, RetryWithMaxBackoff, RetryWithBackoffFactor and
+// RetryWithBackoffJitter is used; those options have no effect on s.
+func RetryWithBackoffStrategy(s BackoffStrategy) RetryOption {
+	return func(b *RetryConfig) {
+		b.strategy = s
+	}
+}
+
+// RetryWithMaxRetries sets the maximum number of attempts made, 5 by default.
+// At least one attempt is always made.
 func RetryWithMaxRetries(n int) RetryOption {
 	return func(b *RetryConfig) {
 		b.maxRetries = n
@@ -49,29 +136,178 @@ func RetryWithRetryableErrChecker(ch IsRetryableErrChecker) RetryOption {
 	}
 }
 
+// RetryWithMaxElapsedTime limits the time spent retrying to d, counted from
+// the start of the first attempt. No wait that would end after d has elapsed is
+// started; a *RetriesExhaustedError whose Reason is ErrMaxElapsedTime is
+// returned instead. The duration of attempts themselves is not limited (use
+// a context deadline for that). There is no limit by default.
+func RetryWithMaxElapsedTime(d time.Duration) RetryOption {
+	return func(b *RetryConfig) {
+		b.maxElapsed = d
+	}
+}
+
+// RetryWithBudget sets a RetryBudget shared with other retry loops. Each call
+// deposits into b once and every retry withdraws from it; once b is empty a
+// *RetriesExhaustedError whose Reason is ErrRetryBudgetExhausted is returned
+// instead of retrying.
+func RetryWithBudget(b *RetryBudget) RetryOption {
+	return func(c *RetryConfig) {
+		c.budget = b
+	}
+}
+
+// RetryWithOnRetry sets f to be called after every failed attempt that is
+// going to be retried, with the number of the attempt (starting at 1), the
+// error it returned and how long the wait before the next attempt is.
+func RetryWithOnRetry(f func(attempt int, err error, nextDelay time.Duration)) RetryOption {
+	return func(b *RetryConfig) {
+		b.onRetry = f
+	}
+}
+
+// RetryWithOnGiveUp sets f to be called once retrying stops without success,
+// with the number of attempts made and the error about to be returned.
+func RetryWithOnGiveUp(f func(attempts int, err error)) RetryOption {
+	return func(b *RetryConfig) {
+		b.onGiveUp = f
+	}
+}
+
+// RetryWithClock sets the Clock used to wait between attempts, SystemClock
+// by default.
+func RetryWithClock(c Clock) RetryOption {
+	return func(b *RetryConfig) {
+		b.clock = c
+	}
+}
+
+// giveUp calls the onGiveUp hook if set, returning err.
+func (c *RetryConfig) giveUp(attempts int, err error) error {
+	if c.onGiveUp != nil {
+		c.onGiveUp(attempts, err)
+	}
+	return err
+}
+
+// DoWithRetries calls doer until it succeeds, returns an error that is not
+// retryable (according to the RetryWithRetryableErrChecker option, which
+// defaults to RetryableErrCheck) or the attempts allowed by RetryWithMaxRetries
+// are exhausted, in which case a *RetriesExhaustedError is returned.
+// Attempts are spaced out by an exponential backoff (see
+// RetryWithBackoffStrategy), except where the error of an attempt specifies
+// how long to wait before retrying (see RetryAfter).
 func DoWithRetries(doer func() error, opts ...RetryOption) error {
+	return DoWithRetriesCtx(context.Background(), func(context.Context) error {
+		return doer()
+	}, opts...)
+}
+
+// DoWithRetriesCtx is like DoWithRetries but stops retrying once ctx is done,
+// including while waiting between attempts. ctx is passed on to doer.
+//
+// If ctx is done before doer succeeds, the error returned is a Retryable
+// error that matches ctx.Err() (i.e. context.Canceled or
+// context.DeadlineExceeded) using errors.Is, and also wraps the error from
+// the last attempt if any.
+func DoWithRetriesCtx(ctx context.Context, doer func(ctx context.Context) error, opts ...RetryOption) error {
+	_, err := Retry(ctx, func(ctx context.Context, _ int) (struct{}, error) {
+		return struct{}{}, doer(ctx)
+	}, opts...)
+	return err
+}
+
+// Retry is like DoWithRetriesCtx but for a doer that returns a result, which
+// Retry returns once doer succeeds. doer is also passed the number of the
+// attempt, starting at 1. e.g:
+//
+//	u, err := typederrs.Retry(ctx, func(ctx context.Context, attempt int) (User, error) {
+//	    return fetchUser(ctx, id)
+//	}, typederrs.RetryWithMaxRetries(3))
+func Retry[T any](ctx context.Context, doer func(ctx context.Context, attempt int) (T, error), opts ...RetryOption) (T, error) {
 
 	conf := RetryConfig{
-		backoff: &backoff.Backoff{Min: 2 * time.Second, Max: 5 * time.Minute},
-		checker: &RetryableErrCheck{},
+		backoff:    ExponentialBackoff{Min: 2 * time.Second, Max: 5 * time.Minute},
+		checker:    &RetryableErrCheck{},
 		maxRetries: 5,
+		clock:      SystemClock,
 	}
 	for _, f := range opts {
 		f(&conf)
 	}
 
-	var err error
+	var strategy BackoffStrategy = conf.backoff
+	if conf.strategy != nil {
+		strategy = conf.strategy
+	}
+
+	var zero T
+	var attemptErrs []error
+	var delay time.Duration
+	start := conf.clock.Now()
+	if conf.budget != nil {
+		conf.budget.Deposit()
+	}
+
+	for attempt := 1; ; attempt++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			var lastErr error
+			if len(attemptErrs) > 0 {
+				lastErr = attemptErrs[len(attemptErrs)-1]
+			}
+			return zero, conf.giveUp(attempt-1, retriesAborted(ctxErr, attempt-1, lastErr))
+		}
 
-	for numRetries := 0; numRetries < conf.maxRetries; numRetries++ {
-		err = doer()
+		res, err := doer(ctx, attempt)
 		if err == nil {
-			return nil
+			return res, nil
 		}
 		if !conf.checker.IsRetryableError(err) {
-			return err
+			return zero, conf.giveUp(attempt, err)
+		}
+		attemptErrs = append(attemptErrs, err)
+		if attempt >= conf.maxRetries {
+			return zero, conf.giveUp(attempt, &RetriesExhaustedError{Attempts: attemptErrs})
+		}
+
+		delay = strategy.Backoff(attempt-1, delay)
+		if after, ok := RetryAfter(err); ok {
+			if conf.backoff.Max > 0 && after > conf.backoff.Max {
+				return zero, conf.giveUp(attempt, &RetriesExhaustedError{
+					Attempts: attemptErrs,
+					Reason:   ErrRetryAfterTooLong,
+				})
+			}
+			delay = after
+		}
+		if conf.maxElapsed > 0 && conf.clock.Now().Sub(start)+delay > conf.maxElapsed {
+			return zero, conf.giveUp(attempt, &RetriesExhaustedError{
+				Attempts: attemptErrs,
+				Reason:   ErrMaxElapsedTime,
+			})
+		}
+		if conf.budget != nil && !conf.budget.Withdraw() {
+			return zero, conf.giveUp(attempt, &RetriesExhaustedError{
+				Attempts: attemptErrs,
+				Reason:   ErrRetryBudgetExhausted,
+			})
+		}
+		if conf.onRetry != nil {
+			conf.onRetry(attempt, err, delay)
+		}
+		if ctxErr := conf.clock.Sleep(ctx, delay); ctxErr != nil {
+			return zero, conf.giveUp(attempt, retriesAborted(ctxErr, attempt, err))
 		}
-		time.Sleep(conf.backoff.Duration())
 	}
+}
 
-	return Newf("too many retries: %v", err)
+// retriesAborted returns the error DoWithRetriesCtx returns when ctx is done
+// with ctxErr after the given number of attempts, lastErr being the error
+// returned by the last attempt.
+func retriesAborted(ctxErr error, attempts int, lastErr error) error {
+	if lastErr == nil {
+		return WrapRetryable(ctxErr, "retries aborted")
+	}
+	return WrapRetryablef(goerrors.Join(ctxErr, lastErr),
+		"retries aborted after %d attempts", attempts)
 }
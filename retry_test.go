@@ -0,0 +1,371 @@
+package errors_test
+
+import (
+	"context"
+	goerrors "errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/tomogoma/go-typed-errors"
+	"github.com/tomogoma/go-typed-errors/errorstest"
+)
+
+// fastRetries keeps tests from waiting on the default backoff.
+var fastRetries = []errors.RetryOption{
+	errors.RetryWithMinBackoff(time.Millisecond),
+	errors.RetryWithMaxBackoff(time.Millisecond),
+}
+
+func TestDoWithRetries(t *testing.T) {
+	tt := []struct {
+		name        string
+		errs        []error
+		expAttempts int
+		expErr      bool
+	}{
+		{name: "first-attempt", errs: []error{nil}, expAttempts: 1},
+		{name: "after-retries", errs: []error{errors.NewRetryable("1"), errors.NewRetryable("2"), nil},
+			expAttempts: 3},
+		{name: "not-retryable", errs: []error{errors.NewRetryable("1"), errors.NewClient("bad")},
+			expAttempts: 2, expErr: true},
+		{name: "too-many-retries", errs: []error{errors.NewRetryable("1"), errors.NewRetryable("2"),
+			errors.NewRetryable("3"), errors.NewRetryable("4"), errors.NewRetryable("5")},
+			expAttempts: 5, expErr: true},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			attempts := 0
+			err := errors.DoWithRetries(func() error {
+				err := tc.errs[attempts]
+				attempts++
+				return err
+			}, fastRetries...)
+			if attempts != tc.expAttempts {
+				t.Errorf("expected %d attempts, got %d", tc.expAttempts, attempts)
+			}
+			if tc.expErr != (err != nil) {
+				t.Errorf("expected error %t, got %v", tc.expErr, err)
+			}
+		})
+	}
+}
+
+func TestDoWithRetriesCtx_canceled(t *testing.T) {
+	checker := errors.AllErrCheck{}
+	ctx, cancel := context.WithCancel(context.Background())
+	attempts := 0
+	err := errors.DoWithRetriesCtx(ctx, func(ctx context.Context) error {
+		attempts++
+		cancel()
+		return errors.NewRetryable("unavailable")
+	}, errors.RetryWithMinBackoff(time.Hour), errors.RetryWithMaxBackoff(time.Hour))
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+	if !goerrors.Is(err, context.Canceled) {
+		t.Errorf("expected error matching context.Canceled, got %v", err)
+	}
+	if !checker.IsRetryableError(err) {
+		t.Errorf("expected a retryable error, got %v", err)
+	}
+}
+
+func TestDoWithRetriesCtx_deadlineExceeded(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := errors.DoWithRetriesCtx(ctx, func(ctx context.Context) error {
+		return errors.NewRetryable("unavailable")
+	}, errors.RetryWithMinBackoff(time.Hour), errors.RetryWithMaxBackoff(time.Hour))
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the wait to be aborted at the deadline, took %v", elapsed)
+	}
+	if !goerrors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected error matching context.DeadlineExceeded, got %v", err)
+	}
+	if !goerrors.Is(err, errors.ErrRetryable) {
+		t.Errorf("expected a retryable error, got %v", err)
+	}
+}
+
+func TestDoWithRetriesCtx_alreadyDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := errors.DoWithRetriesCtx(ctx, func(ctx context.Context) error {
+		t.Errorf("expected doer not to be called")
+		return nil
+	})
+	if !goerrors.Is(err, context.Canceled) {
+		t.Errorf("expected error matching context.Canceled, got %v", err)
+	}
+}
+
+func TestDoWithRetries_exhausted(t *testing.T) {
+	checker := errors.AllErrCheck{}
+	errConn := goerrors.New("connection refused")
+	attemptErrs := []error{
+		errors.NewRetryable("first"),
+		errors.WrapRetryable(errConn, "second"),
+		kindLocked.NewWithHttp("resource locked", "third"),
+	}
+	attempts := 0
+	err := errors.DoWithRetries(func() error {
+		err := attemptErrs[attempts]
+		attempts++
+		return err
+	}, append(fastRetries, errors.RetryWithMaxRetries(3))...)
+
+	if !goerrors.Is(err, errors.ErrRetriesExhausted) {
+		t.Fatalf("expected error matching ErrRetriesExhausted, got %v", err)
+	}
+	var exhausted *errors.RetriesExhaustedError
+	if !goerrors.As(err, &exhausted) || len(exhausted.Attempts) != 3 {
+		t.Fatalf("expected a RetriesExhaustedError with 3 attempts, got %#v", err)
+	}
+	if exhausted.Last() != attemptErrs[2] {
+		t.Errorf("expected Last() to be the error of the last attempt, got %v", exhausted.Last())
+	}
+	if err.Error() != "too many retries: third" {
+		t.Errorf("expected error message '%s', got '%s'", "too many retries: third", err.Error())
+	}
+	if !goerrors.Is(err, errConn) {
+		t.Errorf("expected errors of earlier attempts to be reachable in %v", err)
+	}
+	if !checker.IsKindError(err, kindLocked) {
+		t.Errorf("expected the error to be classified as the last attempt's error")
+	}
+	w := httptest.NewRecorder()
+	status, _ := errors.ErrToHTTP{}.ToHTTPResponse(err, w)
+	if status != http.StatusLocked || w.Body.String() != "resource locked\n" {
+		t.Errorf("expected the response of the last attempt's error, got %d '%s'", status, w.Body.String())
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var gotAttempts []int
+	res, err := errors.Retry(context.Background(), func(ctx context.Context, attempt int) (string, error) {
+		gotAttempts = append(gotAttempts, attempt)
+		if attempt < 3 {
+			return "partial", errors.NewRetryablef("attempt %d", attempt)
+		}
+		return "done", nil
+	}, fastRetries...)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if res != "done" {
+		t.Errorf("expected result 'done', got '%s'", res)
+	}
+	if len(gotAttempts) != 3 || gotAttempts[0] != 1 || gotAttempts[2] != 3 {
+		t.Errorf("expected attempts [1 2 3], got %v", gotAttempts)
+	}
+}
+
+func TestRetry_error(t *testing.T) {
+	res, err := errors.Retry(context.Background(), func(ctx context.Context, attempt int) (int, error) {
+		return attempt, errors.NewNotFound("none")
+	}, fastRetries...)
+	if !goerrors.Is(err, errors.ErrNotFound) {
+		t.Errorf("expected the not found error, got %v", err)
+	}
+	if res != 0 {
+		t.Errorf("expected the zero value on error, got %d", res)
+	}
+}
+
+func TestDoWithRetries_retryAfter(t *testing.T) {
+	start := time.Now()
+	attempts := 0
+	err := errors.DoWithRetries(func() error {
+		attempts++
+		if attempts == 1 {
+			return errors.NewRetryableAfter(time.Millisecond, "rate limited")
+		}
+		return nil
+	}, errors.RetryWithMinBackoff(time.Hour), errors.RetryWithMaxBackoff(time.Hour))
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected the error's RetryAfter to replace the backoff, took %v", elapsed)
+	}
+}
+
+func TestDoWithRetries_retryAfterTooLong(t *testing.T) {
+	clock := errorstest.NewFakeClock(time.Now())
+	attempts := 0
+	err := errors.DoWithRetries(func() error {
+		attempts++
+		return errors.NewRetryableAfter(time.Hour, "rate limited")
+	}, errors.RetryWithClock(clock), errors.RetryWithMaxBackoff(time.Second))
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+	if sleeps := clock.Sleeps(); len(sleeps) != 0 {
+		t.Errorf("expected no sleeps, got %v", sleeps)
+	}
+	if !goerrors.Is(err, errors.ErrRetryAfterTooLong) || !goerrors.Is(err, errors.ErrRetriesExhausted) {
+		t.Errorf("expected error matching ErrRetryAfterTooLong and ErrRetriesExhausted, got %v", err)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	err := fmt.Errorf("call: %w", errors.WrapRetryableAfter(goerrors.New("429"), 30*time.Second, "upstream"))
+	if d, ok := errors.RetryAfter(err); !ok || d != 30*time.Second {
+		t.Errorf("expected (30s, true), got (%v, %t)", d, ok)
+	}
+	if d, ok := errors.RetryAfter(errors.NewRetryable("no delay")); ok {
+		t.Errorf("expected no RetryAfter, got %v", d)
+	}
+}
+
+func TestError_ToHTTPResponse_retryAfter(t *testing.T) {
+	tt := []struct {
+		name          string
+		err           errors.Error
+		expRetryAfter string
+	}{
+		{name: "rounded-up", err: errors.NewRetryableAfterf(1500*time.Millisecond, "later"), expRetryAfter: "2"},
+		{name: "none", err: errors.NewRetryable("later"), expRetryAfter: ""},
+		{name: "not-503", err: errors.Error{IsConflictErr: true, RetryAfter: time.Second}, expRetryAfter: ""},
+		{name: "wrapped", err: errors.WrapRetryable(errors.NewRetryableAfter(30*time.Second, "busy"), "call upstream"),
+			expRetryAfter: "30"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tc.err.ToHTTPResponse(w)
+			if ra := w.Header().Get("Retry-After"); ra != tc.expRetryAfter {
+				t.Errorf("expected Retry-After '%s', got '%s'", tc.expRetryAfter, ra)
+			}
+		})
+	}
+}
+
+func TestDoWithRetries_hooks(t *testing.T) {
+	type retried struct {
+		attempt int
+		err     error
+		delay   time.Duration
+	}
+	tt := []struct {
+		name              string
+		errs              []error
+		expRetried        []int
+		expGiveUp         bool
+		expGiveUpAttempts int
+	}{
+		{name: "success", errs: []error{errors.NewRetryable("1"), nil}, expRetried: []int{1}},
+		{name: "not-retryable", errs: []error{errors.NewRetryable("1"), errors.NewClient("2")},
+			expRetried: []int{1}, expGiveUp: true, expGiveUpAttempts: 2},
+		{name: "exhausted", errs: []error{errors.NewRetryable("1"), errors.NewRetryable("2"), errors.NewRetryable("3")},
+			expRetried: []int{1, 2}, expGiveUp: true, expGiveUpAttempts: 3},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var gotRetried []retried
+			var gaveUp bool
+			var gaveUpAttempts int
+			var gaveUpErr error
+			attempts := 0
+			err := errors.DoWithRetries(func() error {
+				err := tc.errs[attempts]
+				attempts++
+				return err
+			}, append(fastRetries,
+				errors.RetryWithMaxRetries(3),
+				errors.RetryWithOnRetry(func(attempt int, err error, nextDelay time.Duration) {
+					gotRetried = append(gotRetried, retried{attempt: attempt, err: err, delay: nextDelay})
+				}),
+				errors.RetryWithOnGiveUp(func(attempts int, err error) {
+					gaveUp, gaveUpAttempts, gaveUpErr = true, attempts, err
+				}),
+			)...)
+			if len(gotRetried) != len(tc.expRetried) {
+				t.Fatalf("expected OnRetry for attempts %v, got %+v", tc.expRetried, gotRetried)
+			}
+			for i, r := range gotRetried {
+				if r.attempt != tc.expRetried[i] || r.err != tc.errs[i] || r.delay != time.Millisecond {
+					t.Errorf("expected OnRetry(%d, %v, 1ms), got %+v", tc.expRetried[i], tc.errs[i], r)
+				}
+			}
+			if gaveUp != tc.expGiveUp {
+				t.Fatalf("expected OnGiveUp called %t", tc.expGiveUp)
+			}
+			if gaveUp && (gaveUpAttempts != tc.expGiveUpAttempts || gaveUpErr != err) {
+				t.Errorf("expected OnGiveUp(%d, %v), got OnGiveUp(%d, %v)",
+					tc.expGiveUpAttempts, err, gaveUpAttempts, gaveUpErr)
+			}
+		})
+	}
+}
+
+func TestDoWithRetries_fakeClock(t *testing.T) {
+	clock := errorstest.NewFakeClock(time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC))
+	err := errors.DoWithRetries(func() error {
+		return errors.NewRetryable("unavailable")
+	}, errors.RetryWithClock(clock))
+	if !goerrors.Is(err, errors.ErrRetriesExhausted) {
+		t.Fatalf("expected error matching ErrRetriesExhausted, got %v", err)
+	}
+	expSleeps := []time.Duration{2 * time.Second, 4 * time.Second, 8 * time.Second, 16 * time.Second}
+	if fmt.Sprint(clock.Sleeps()) != fmt.Sprint(expSleeps) {
+		t.Errorf("expected sleeps %v, got %v", expSleeps, clock.Sleeps())
+	}
+	if exp := time.Date(2020, 1, 1, 0, 0, 30, 0, time.UTC); !clock.Now().Equal(exp) {
+		t.Errorf("expected the clock to be at %v, got %v", exp, clock.Now())
+	}
+}
+
+func TestDoWithRetriesCtx_fakeClockCanceledWhileWaiting(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	clock := errorstest.NewFakeClock(time.Now())
+	clock.OnSleep = func(d time.Duration) { cancel() }
+	attempts := 0
+	err := errors.DoWithRetriesCtx(ctx, func(ctx context.Context) error {
+		attempts++
+		return errors.NewRetryable("unavailable")
+	}, errors.RetryWithClock(clock))
+	if attempts != 1 {
+		t.Errorf("expected 1 attempt, got %d", attempts)
+	}
+	if !goerrors.Is(err, context.Canceled) {
+		t.Errorf("expected error matching context.Canceled, got %v", err)
+	}
+	if len(clock.Sleeps()) != 0 {
+		t.Errorf("expected no completed sleeps, got %v", clock.Sleeps())
+	}
+}
+
+func TestDoWithRetries_maxElapsedTime(t *testing.T) {
+	clock := errorstest.NewFakeClock(time.Now())
+	attempts := 0
+	err := errors.DoWithRetries(func() error {
+		attempts++
+		clock.Advance(time.Second) // each attempt takes a second
+		return errors.NewRetryablef("attempt %d", attempts)
+	},
+		errors.RetryWithClock(clock),
+		errors.RetryWithMaxRetries(10),
+		errors.RetryWithBackoffStrategy(errors.ConstantBackoff{Delay: 3 * time.Second}),
+		errors.RetryWithMaxElapsedTime(10*time.Second),
+	)
+	// attempt 1 ends at 1s, attempt 2 at 5s, attempt 3 at 9s; waiting 3s
+	// more would end at 12s.
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+	if len(clock.Sleeps()) != 2 {
+		t.Errorf("expected 2 sleeps, got %v", clock.Sleeps())
+	}
+	if !goerrors.Is(err, errors.ErrMaxElapsedTime) || !goerrors.Is(err, errors.ErrRetriesExhausted) {
+		t.Errorf("expected error matching ErrMaxElapsedTime and ErrRetriesExhausted, got %v", err)
+	}
+	if exp := "retry time budget exceeded: attempt 3"; err.Error() != exp {
+		t.Errorf("expected error message '%s', got '%s'", exp, err.Error())
+	}
+}
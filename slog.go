@@ -0,0 +1,145 @@
+package errors
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RedactedValue replaces the values of attributes redacted by a SlogHandler.
+const RedactedValue = "[REDACTED]"
+
+// LogValue implements slog.LogValuer, logging the error as a group of its
+// class, code (see JSONError), public message (HttpMsg, if any), internal
+// message (Error()), RetryAfter (if any), Fields (public or not, if any) and
+// Cause (if any) e.g:
+//
+//	err={class=not_found code=2 public_message="user not found" internal_message="fetch user: sql: no rows in result set" cause={internal_message="sql: no rows in result set"}}
+//
+// A Cause is logged as per LogValue if it is or wraps an Error, otherwise as a
+// group of its internal message alone.
+func (e Error) LogValue() slog.Value {
+	return slog.GroupValue(e.logAttrs(e.Error())...)
+}
+
+// logAttrs returns the attributes LogValue groups, with msg as the internal
+// message.
+func (e Error) logAttrs(msg string) []slog.Attr {
+	k := e.Kind()
+	attrs := []slog.Attr{
+		slog.String("class", k.String()),
+		slog.Int("code", int(k)),
+	}
+	if e.HttpMsg != "" {
+		attrs = append(attrs, slog.String("public_message", e.HttpMsg))
+	}
+	attrs = append(attrs, slog.String("internal_message", msg))
+	if e.RetryAfter > 0 {
+		attrs = append(attrs, slog.Duration("retry_after", e.RetryAfter))
+	}
+	if e.fields != nil {
+		attrs = append(attrs, slog.Group("fields", e.fieldAttrs()...))
+	}
+	if e.Cause != nil {
+		attrs = append(attrs, slog.Attr{Key: "cause", Value: causeLogValue(e.Cause)})
+	}
+	return attrs
+}
+
+// causeLogValue returns the group a Cause is logged as: as per LogValue if an
+// Error is found in its chain, otherwise just its message as the internal
+// message, such that the internal details of the whole chain are found under
+// "internal_message" keys.
+func causeLogValue(cause error) slog.Value {
+	if e, ok := findErr(cause, func(Error) bool { return true }); ok {
+		return slog.GroupValue(e.logAttrs(cause.Error())...)
+	}
+	return slog.GroupValue(slog.String("internal_message", cause.Error()))
+}
+
+// SlogHandler is a slog.Handler that expands errors logged as attribute
+// values into groups as per Error.LogValue before passing records on to the
+// next handler. Unlike slog's own handlers which only expand Errors
+// themselves, it also expands errors that wrap an Error, using the class of
+// the first Error in the chain and the message of the whole error.
+// It can also redact attributes, including those of expanded errors (see
+// SlogWithRedactedKeys).
+type SlogHandler struct {
+	next   slog.Handler
+	redact map[string]bool
+}
+
+type SlogOption func(*SlogHandler)
+
+// SlogWithRedactedKeys sets the keys of attributes whose values are replaced
+// with RedactedValue, at any level of nesting e.g. "internal_message" to keep
+// internal details of errors out of logs, including those of their causes
+// (see Error.LogValue).
+func SlogWithRedactedKeys(keys ...string) SlogOption {
+	return func(h *SlogHandler) {
+		for _, k := range keys {
+			h.redact[k] = true
+		}
+	}
+}
+
+// NewSlogHandler creates a new SlogHandler passing records on to next e.g:
+//
+//	logger := slog.New(typederrs.NewSlogHandler(slog.NewJSONHandler(os.Stderr, nil)))
+func NewSlogHandler(next slog.Handler, opts ...SlogOption) *SlogHandler {
+	h := &SlogHandler{next: next, redact: make(map[string]bool)}
+	for _, f := range opts {
+		f(h)
+	}
+	return h
+}
+
+func (h *SlogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	expanded := slog.NewRecord(r.Time, r.Level, r.Message, r.PC)
+	r.Attrs(func(a slog.Attr) bool {
+		expanded.AddAttrs(h.attr(a))
+		return true
+	})
+	return h.next.Handle(ctx, expanded)
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	expanded := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		expanded[i] = h.attr(a)
+	}
+	return &SlogHandler{next: h.next.WithAttrs(expanded), redact: h.redact}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	return &SlogHandler{next: h.next.WithGroup(name), redact: h.redact}
+}
+
+// attr returns a with its value resolved, errors expanded and redactions
+// applied, recursively for groups.
+func (h *SlogHandler) attr(a slog.Attr) slog.Attr {
+	if h.redact[a.Key] {
+		return slog.String(a.Key, RedactedValue)
+	}
+	v := a.Value
+	if v.Kind() == slog.KindAny {
+		if err, ok := v.Any().(error); ok {
+			if e, ok := findErr(err, func(Error) bool { return true }); ok {
+				v = slog.GroupValue(e.logAttrs(err.Error())...)
+			}
+		}
+	}
+	v = v.Resolve()
+	if v.Kind() != slog.KindGroup {
+		return slog.Attr{Key: a.Key, Value: v}
+	}
+	group := v.Group()
+	attrs := make([]slog.Attr, len(group))
+	for i, ga := range group {
+		attrs[i] = h.attr(ga)
+	}
+	return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+}
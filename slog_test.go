@@ -0,0 +1,113 @@
+package errors_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func TestError_LogValue(t *testing.T) {
+	err := errors.WrapNotFoundWithHttp(errDriver, "user not found", "fetch user 7")
+	var buf bytes.Buffer
+	slog.New(slog.NewJSONHandler(&buf, nil)).Info("failed", "err", err)
+
+	exp := map[string]interface{}{
+		"class":            "not_found",
+		"code":             float64(errors.KindNotFound),
+		"public_message":   "user not found",
+		"internal_message": "fetch user 7: driver: no rows in result set",
+		"cause":            map[string]interface{}{"internal_message": "driver: no rows in result set"},
+	}
+	if got := decodeLogAttr(t, buf.Bytes(), "err"); !reflect.DeepEqual(got, exp) {
+		t.Errorf("expected %v, got %v", exp, got)
+	}
+}
+
+func TestSlogHandler(t *testing.T) {
+	retryable := errors.NewRetryableAfter(time.Second, "db overloaded")
+	tt := []struct {
+		name string
+		opts []errors.SlogOption
+		err  error
+		exp  map[string]interface{}
+	}{
+		{
+			name: "wrapped",
+			err:  fmt.Errorf("fetch user: %w", retryable),
+			exp: map[string]interface{}{
+				"class":            "retryable",
+				"code":             float64(errors.KindRetryable),
+				"internal_message": "fetch user: db overloaded",
+				"retry_after":      float64(time.Second),
+			},
+		},
+		{
+			name: "nested-cause-redacted",
+			opts: []errors.SlogOption{errors.SlogWithRedactedKeys("internal_message")},
+			err:  errors.WrapClient(fmt.Errorf("validate: %w", retryable), "bad request"),
+			exp: map[string]interface{}{
+				"class":            "client",
+				"code":             float64(errors.KindClient),
+				"internal_message": errors.RedactedValue,
+				"cause": map[string]interface{}{
+					"class":            "retryable",
+					"code":             float64(errors.KindRetryable),
+					"internal_message": errors.RedactedValue,
+					"retry_after":      float64(time.Second),
+				},
+			},
+		},
+		{
+			name: "plain-cause-redacted",
+			opts: []errors.SlogOption{errors.SlogWithRedactedKeys("internal_message")},
+			err:  errors.WrapNotFound(errDriver, "fetch user"),
+			exp: map[string]interface{}{
+				"class":            "not_found",
+				"code":             float64(errors.KindNotFound),
+				"internal_message": errors.RedactedValue,
+				"cause":            map[string]interface{}{"internal_message": errors.RedactedValue},
+			},
+		},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			h := errors.NewSlogHandler(slog.NewJSONHandler(&buf, nil), tc.opts...)
+			slog.New(h).WithGroup("req").Info("failed", "err", tc.err)
+			req, ok := decodeLogAttr(t, buf.Bytes(), "req").(map[string]interface{})
+			if !ok {
+				t.Fatalf("expected group req, got %s", buf.String())
+			}
+			if got := req["err"]; !reflect.DeepEqual(got, tc.exp) {
+				t.Errorf("expected %v, got %v", tc.exp, got)
+			}
+		})
+	}
+}
+
+func TestSlogHandler_WithAttrs(t *testing.T) {
+	var buf bytes.Buffer
+	h := errors.NewSlogHandler(slog.NewJSONHandler(&buf, nil), errors.SlogWithRedactedKeys("token"))
+	slog.New(h).With("token", "secret", "err", fmt.Errorf("x: %w", errors.NewNotFound("y"))).Info("failed")
+	if got := decodeLogAttr(t, buf.Bytes(), "token"); got != errors.RedactedValue {
+		t.Errorf("expected token redacted, got %v", got)
+	}
+	errAttr, _ := decodeLogAttr(t, buf.Bytes(), "err").(map[string]interface{})
+	if errAttr["class"] != "not_found" {
+		t.Errorf("expected err expanded, got %s", buf.String())
+	}
+}
+
+func decodeLogAttr(t *testing.T, line []byte, key string) interface{} {
+	var rec map[string]interface{}
+	if err := json.Unmarshal(line, &rec); err != nil {
+		t.Fatalf("unmarshal log record %s: %v", line, err)
+	}
+	return rec[key]
+}
@@ -0,0 +1,88 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync/atomic"
+)
+
+// maxStackDepth is the maximum number of frames captured.
+const maxStackDepth = 32
+
+var stackCapture atomic.Bool
+
+// pkgPath is the import path of this package, used to trim its own frames
+// from captured stack traces.
+var pkgPath = reflect.TypeOf(Error{}).PkgPath()
+
+// SetStackCapture sets whether Errors created by the New... and Wrap...
+// functions capture the stack trace of their creation (see
+// Error.StackTrace). Capture is disabled by default as it slows down the
+// creation of errors.
+func SetStackCapture(enabled bool) {
+	stackCapture.Store(enabled)
+}
+
+// StackCapture reports whether stack trace capture is enabled.
+func StackCapture() bool {
+	return stackCapture.Load()
+}
+
+// stack is the program counters of a captured stack trace.
+type stack []uintptr
+
+// callers returns the stack trace of its caller if stack capture is
+// enabled, nil otherwise.
+func callers() *stack {
+	if !StackCapture() {
+		return nil
+	}
+	var pcs [maxStackDepth]uintptr
+	n := runtime.Callers(3, pcs[:])
+	s := stack(pcs[:n])
+	return &s
+}
+
+// frames returns the frames of s, skipping the leading frames in this
+// package such that the first frame is where the Error was created from.
+func (s *stack) frames() []runtime.Frame {
+	if s == nil {
+		return nil
+	}
+	var frames []runtime.Frame
+	iter := runtime.CallersFrames(*s)
+	for {
+		f, more := iter.Next()
+		if len(frames) > 0 || !inPackage(f.Function) {
+			frames = append(frames, f)
+		}
+		if !more {
+			return frames
+		}
+	}
+}
+
+// inPackage reports whether function belongs to this package (as opposed to
+// e.g. its tests).
+func inPackage(function string) bool {
+	rest, ok := strings.CutPrefix(function, pkgPath)
+	return ok && strings.HasPrefix(rest, ".")
+}
+
+// writeStack writes frames one per line as the function name followed by the
+// file and line on an indented line.
+func writeStack(w io.Writer, frames []runtime.Frame) {
+	for _, f := range frames {
+		fmt.Fprintf(w, "\n%s\n\t%s:%d", f.Function, f.File, f.Line)
+	}
+}
+
+// StackTrace returns the stack trace captured when the error was created,
+// starting with the caller of the function that created it. It returns nil if
+// stack capture was disabled (see SetStackCapture).
+func (e Error) StackTrace() []runtime.Frame {
+	return e.stack.frames()
+}
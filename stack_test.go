@@ -0,0 +1,48 @@
+package errors_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+func newNotFound() error {
+	return errors.NewNotFoundf("user %d not found", 7)
+}
+
+func TestError_StackTrace(t *testing.T) {
+	errors.SetStackCapture(true)
+	defer errors.SetStackCapture(false)
+
+	err := newNotFound().(errors.Error)
+	frames := err.StackTrace()
+	if len(frames) == 0 {
+		t.Fatalf("expected a stack trace")
+	}
+	if exp := "go-typed-errors_test.newNotFound"; !strings.HasSuffix(frames[0].Function, exp) {
+		t.Errorf("expected first frame in %s, got %s", exp, frames[0].Function)
+	}
+	if exp := "go-typed-errors_test.TestError_StackTrace"; !strings.HasSuffix(frames[1].Function, exp) {
+		t.Errorf("expected second frame in %s, got %s", exp, frames[1].Function)
+	}
+
+	out := fmt.Sprintf("%+v", err)
+	if !strings.HasPrefix(out, "not_found: user 7 not found\n") || !strings.Contains(out, "stack_test.go:") {
+		t.Errorf("expected message followed by the stack trace, got %s", out)
+	}
+	if out := fmt.Sprintf("%v", err); out != "user 7 not found" {
+		t.Errorf("expected %%v to print the message only, got %s", out)
+	}
+}
+
+func TestError_StackTrace_disabled(t *testing.T) {
+	err := errors.NewRetryable("unavailable")
+	if frames := err.StackTrace(); frames != nil {
+		t.Errorf("expected no stack trace, got %v", frames)
+	}
+	if out := fmt.Sprintf("%+v", err); out != "retryable: unavailable" {
+		t.Errorf("expected the class and message only, got %s", out)
+	}
+}
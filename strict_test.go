@@ -0,0 +1,119 @@
+package errors_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/tomogoma/go-typed-errors"
+	"github.com/tomogoma/go-typed-errors/errorstest"
+)
+
+func TestErrToHTTP_strict(t *testing.T) {
+	tt := []struct {
+		name    string
+		err     error
+		expBody string
+	}{
+		{name: "not-found", err: errors.WrapNotFound(errDriver, "select * from users where id = 7"),
+			expBody: "resource not found\n"},
+		{name: "public-message", err: errors.NewConflictWithHttp("email taken", "unique violation on users_email_key"),
+			expBody: "email taken\n"},
+		{name: "registered-with-parent", err: kindGone.New("archived row 7"),
+			expBody: "resource not found\n"},
+		{name: "registered-without-parent", err: kindLocked.New("row lock held by pid 42"),
+			expBody: "locked\n"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if _, ok := (errors.ErrToHTTP{Strict: true}).ToHTTPResponse(tc.err, w); !ok {
+				t.Fatalf("expected error written")
+			}
+			if w.Body.String() != tc.expBody {
+				t.Errorf("expected body '%s', got '%s'", tc.expBody, w.Body.String())
+			}
+			errorstest.AssertNoLeak(t, w, tc.err)
+		})
+	}
+}
+
+func TestErrToHTTP_strictFromHTTPResponse(t *testing.T) {
+	resp := &http.Response{
+		StatusCode: http.StatusServiceUnavailable,
+		Status:     "503 Service Unavailable",
+		Header:     http.Header{"Content-Type": {"text/plain"}},
+		Body:       io.NopCloser(strings.NewReader("redis 10.0.0.3:6379 connection refused")),
+	}
+	err, ok := errors.FromHTTPResponse(resp)
+	if !ok {
+		t.Fatalf("expected an error")
+	}
+	w := httptest.NewRecorder()
+	errors.ErrToHTTP{Strict: true}.ToHTTPResponse(err, w)
+	if exp := "service unavailable\n"; w.Body.String() != exp {
+		t.Errorf("expected body '%s', got '%s'", exp, w.Body.String())
+	}
+	errorstest.AssertNoLeak(t, w, err)
+}
+
+func TestStrictHTTPMessages(t *testing.T) {
+	errors.StrictHTTPMessages = true
+	defer func() { errors.StrictHTTPMessages = false }()
+
+	err := errors.NewRetryable("redis: connection refused")
+	w := httptest.NewRecorder()
+	errors.HandlerFunc(func(http.ResponseWriter, *http.Request) error {
+		return err
+	}).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	if exp := "service unavailable\n"; w.Body.String() != exp {
+		t.Errorf("expected body '%s', got '%s'", exp, w.Body.String())
+	}
+	errorstest.AssertNoLeak(t, w, err)
+}
+
+func TestAssertNoLeak(t *testing.T) {
+	err := errors.WrapClient(errDriver, "parse filter")
+	w := httptest.NewRecorder()
+	errors.ErrToHTTP{}.ToHTTPResponse(err, w)
+
+	rec := &recordingTB{TB: t}
+	errorstest.AssertNoLeak(rec, w, err)
+	if !rec.failed {
+		t.Errorf("expected a leak to be reported for body '%s'", w.Body.String())
+	}
+}
+
+// recordingTB records failures instead of failing the test.
+type recordingTB struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recordingTB) Helper() {}
+
+func (r *recordingTB) Errorf(format string, args ...interface{}) {
+	r.failed = true
+}
+
+func TestKind_HTTPMessage(t *testing.T) {
+	tt := []struct {
+		kind errors.Kind
+		exp  string
+	}{
+		{kind: errors.KindUnknown, exp: "internal error"},
+		{kind: errors.KindNotFound, exp: "resource not found"},
+		{kind: kindQuotaExceeded, exp: "bad request"},
+		{kind: kindLocked, exp: "locked"},
+		{kind: errors.Kind(99), exp: "internal error"},
+	}
+	for _, tc := range tt {
+		t.Run(tc.kind.String(), func(t *testing.T) {
+			if got := tc.kind.HTTPMessage(); got != tc.exp {
+				t.Errorf("expected '%s', got '%s'", tc.exp, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+package errors
+
+import (
+	"fmt"
+	"time"
+)
+
+// Wrap creates an error with err as its Cause.
+//
+// Like the rest of the Wrap... functions, the Cause remains reachable through
+// errors.Is and errors.As while the checkers and ToHTTPResponse work off
+// the class and HttpMsg of the returned Error. data may be nil, in which case
+// the error message is that of the Cause.
+func Wrap(err error, data interface{}) Error {
+	return newError(KindUnknown, err, "", data)
+}
+
+// Wrapf creates an error with err as its Cause and fmt.Printf style formatting.
+func Wrapf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return Wrap(err, data)
+}
+
+// WrapWithHttp creates an error with err as its Cause containing
+// a http specific error message.
+func WrapWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindUnknown, err, httpMsg, data)
+}
+
+// WrapWithHttpf creates an error with err as its Cause containing
+// a http specific error message.
+func WrapWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapWithHttp(err, httpMsg, data)
+}
+
+// WrapClient creates a client error with err as its Cause.
+func WrapClient(err error, data interface{}) Error {
+	return newError(KindClient, err, "", data)
+}
+
+// WrapClientf creates a client error with err as its Cause and fmt.Printf style formatting.
+func WrapClientf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapClient(err, data)
+}
+
+// WrapClientWithHttp creates a client error with err as its Cause containing
+// a http specific error message.
+func WrapClientWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindClient, err, httpMsg, data)
+}
+
+// WrapClientWithHttpf creates a client error with err as its Cause containing
+// a http specific error message.
+func WrapClientWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapClientWithHttp(err, httpMsg, data)
+}
+
+// WrapNotImplemented creates a not implemented error with err as its Cause.
+func WrapNotImplemented(err error, data interface{}) Error {
+	return newError(KindNotImplemented, err, "", data)
+}
+
+// WrapNotImplementedf creates a not implemented error with err as its Cause and fmt.Printf style formatting.
+func WrapNotImplementedf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapNotImplemented(err, data)
+}
+
+// WrapNotImplementedWithHttp creates a not implemented error with err as its Cause containing
+// a http specific error message.
+func WrapNotImplementedWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindNotImplemented, err, httpMsg, data)
+}
+
+// WrapNotImplementedWithHttpf creates a not implemented error with err as its Cause containing
+// a http specific error message.
+func WrapNotImplementedWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapNotImplementedWithHttp(err, httpMsg, data)
+}
+
+// WrapAuth creates an auth error with err as its Cause.
+func WrapAuth(err error, data interface{}) Error {
+	return newError(KindAuth, err, "", data)
+}
+
+// WrapAuthf creates an auth error with err as its Cause and fmt.Printf style formatting.
+func WrapAuthf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapAuth(err, data)
+}
+
+// WrapAuthWithHttp creates an auth error with err as its Cause containing
+// a http specific error message.
+func WrapAuthWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindAuth, err, httpMsg, data)
+}
+
+// WrapAuthWithHttpf creates an auth error with err as its Cause containing
+// a http specific error message.
+func WrapAuthWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapAuthWithHttp(err, httpMsg, data)
+}
+
+// WrapForbidden creates a forbidden auth error with err as its Cause.
+// This will also resolve as an Auth error.
+func WrapForbidden(err error, data interface{}) Error {
+	return newError(KindForbidden, err, "", data)
+}
+
+// WrapForbiddenf creates a forbidden auth error with err as its Cause and fmt.Printf style formatting.
+// This will also resolve as an Auth error.
+func WrapForbiddenf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapForbidden(err, data)
+}
+
+// WrapForbiddenWithHttp creates a forbidden auth error with err as its Cause containing
+// a http specific error message.
+func WrapForbiddenWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindForbidden, err, httpMsg, data)
+}
+
+// WrapForbiddenWithHttpf creates a forbidden auth error with err as its Cause containing
+// a http specific error message.
+func WrapForbiddenWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapForbiddenWithHttp(err, httpMsg, data)
+}
+
+// WrapUnauthorized creates an unauthorized auth error with err as its Cause.
+// This will also resolve as an Auth error.
+func WrapUnauthorized(err error, data interface{}) Error {
+	return newError(KindUnauthorized, err, "", data)
+}
+
+// WrapUnauthorizedf creates an unauthorized auth error with err as its Cause and fmt.Printf style formatting.
+// This will also resolve as an Auth error.
+func WrapUnauthorizedf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapUnauthorized(err, data)
+}
+
+// WrapUnauthorizedWithHttp creates an unauthorized auth error with err as its Cause containing
+// a http specific error message.
+func WrapUnauthorizedWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindUnauthorized, err, httpMsg, data)
+}
+
+// WrapUnauthorizedWithHttpf creates an unauthorized auth error with err as its Cause containing
+// a http specific error message.
+func WrapUnauthorizedWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapUnauthorizedWithHttp(err, httpMsg, data)
+}
+
+// WrapNotFound creates a not found error with err as its Cause.
+func WrapNotFound(err error, data interface{}) Error {
+	return newError(KindNotFound, err, "", data)
+}
+
+// WrapNotFoundf creates a not found error with err as its Cause and fmt.Printf style formatting.
+func WrapNotFoundf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapNotFound(err, data)
+}
+
+// WrapNotFoundWithHttp creates a not found error with err as its Cause containing
+// a http specific error message.
+func WrapNotFoundWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindNotFound, err, httpMsg, data)
+}
+
+// WrapNotFoundWithHttpf creates a not found error with err as its Cause containing
+// a http specific error message.
+func WrapNotFoundWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapNotFoundWithHttp(err, httpMsg, data)
+}
+
+// WrapRetryable creates a retryable error with err as its Cause.
+func WrapRetryable(err error, data interface{}) Error {
+	return newError(KindRetryable, err, "", data)
+}
+
+// WrapRetryablef creates a retryable error with err as its Cause and fmt.Printf style formatting.
+func WrapRetryablef(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapRetryable(err, data)
+}
+
+// WrapRetryableWithHttp creates a retryable error with err as its Cause containing
+// a http specific error message.
+func WrapRetryableWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindRetryable, err, httpMsg, data)
+}
+
+// WrapRetryableWithHttpf creates a retryable error with err as its Cause containing
+// a http specific error message.
+func WrapRetryableWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapRetryableWithHttp(err, httpMsg, data)
+}
+
+// WrapRetryableAfter creates a retryable error with err as its Cause that
+// should not be retried before after has elapsed.
+func WrapRetryableAfter(err error, after time.Duration, data interface{}) Error {
+	e := newError(KindRetryable, err, "", data)
+	e.RetryAfter = after
+	return e
+}
+
+// WrapConflict creates a Conflict error with err as its Cause.
+func WrapConflict(err error, data interface{}) Error {
+	return newError(KindConflict, err, "", data)
+}
+
+// WrapConflictf creates a Conflict error with err as its Cause and fmt.Printf style formatting.
+func WrapConflictf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapConflict(err, data)
+}
+
+// WrapConflictWithHttp creates a Conflict error with err as its Cause containing
+// a http specific error message.
+func WrapConflictWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindConflict, err, httpMsg, data)
+}
+
+// WrapConflictWithHttpf creates a Conflict error with err as its Cause containing
+// a http specific error message.
+func WrapConflictWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapConflictWithHttp(err, httpMsg, data)
+}
+
+// WrapPreconditionFailed creates a PreconditionFailed error with err as its Cause.
+func WrapPreconditionFailed(err error, data interface{}) Error {
+	return newError(KindPreconditionFailed, err, "", data)
+}
+
+// WrapPreconditionFailedf creates a PreconditionFailed error with err as its Cause and fmt.Printf style formatting.
+func WrapPreconditionFailedf(err error, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapPreconditionFailed(err, data)
+}
+
+// WrapPreconditionFailedWithHttp creates a PreconditionFailed error with err as its Cause containing
+// a http specific error message.
+func WrapPreconditionFailedWithHttp(err error, httpMsg string, data interface{}) Error {
+	return newError(KindPreconditionFailed, err, httpMsg, data)
+}
+
+// WrapPreconditionFailedWithHttpf creates a PreconditionFailed error with err as its Cause containing
+// a http specific error message.
+func WrapPreconditionFailedWithHttpf(err error, httpMsg string, format string, a ...interface{}) Error {
+	data := fmt.Sprintf(format, a...)
+	return WrapPreconditionFailedWithHttp(err, httpMsg, data)
+}
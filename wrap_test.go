@@ -0,0 +1,85 @@
+package errors_test
+
+import (
+	goerrors "errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/tomogoma/go-typed-errors"
+)
+
+var errDriver = goerrors.New("driver: no rows in result set")
+
+func TestWrapNotFound(t *testing.T) {
+	checker := errors.AllErrCheck{}
+	err := errors.WrapNotFound(errDriver, "fetch user")
+	if err.Error() != "fetch user: "+errDriver.Error() {
+		t.Errorf("expected error message '%s', got '%s'",
+			"fetch user: "+errDriver.Error(), err.Error())
+	}
+	if !goerrors.Is(err, errDriver) {
+		t.Errorf("expected errors.Is to find the wrapped error in %v", err)
+	}
+	if !checker.IsNotFoundError(err) {
+		t.Errorf("Expected IsNotFoundError() true but got %t",
+			checker.IsNotFoundError(err))
+	}
+}
+
+func TestWrapRetryablef(t *testing.T) {
+	checker := errors.AllErrCheck{}
+	errDial := goerrors.New("dial tcp: connection refused")
+	err := errors.WrapRetryablef(errDial, "connect to %s", "db")
+	if err.Error() != "connect to db: "+errDial.Error() {
+		t.Errorf("expected error message '%s', got '%s'",
+			"connect to db: "+errDial.Error(), err.Error())
+	}
+	if !goerrors.Is(err, errDial) {
+		t.Errorf("expected errors.Is to find the wrapped error in %v", err)
+	}
+	if !checker.IsRetryableError(err) {
+		t.Errorf("Expected IsRetryableError() true but got %t",
+			checker.IsRetryableError(err))
+	}
+}
+
+func TestWrapForbidden(t *testing.T) {
+	checker := errors.AllErrCheck{}
+	err := errors.WrapForbidden(errDriver, nil)
+	if err.Error() != errDriver.Error() {
+		t.Errorf("expected error message '%s', got '%s'",
+			errDriver.Error(), err.Error())
+	}
+	if !checker.IsAuthError(err) || !checker.IsForbiddenError(err) {
+		t.Errorf("Expected IsAuthError() and IsForbiddenError() true for %v", err)
+	}
+}
+
+func TestWrapConflictWithHttp(t *testing.T) {
+	err := errors.WrapConflictWithHttp(errDriver, "already exists", "insert user")
+	var target errors.Error
+	if !goerrors.As(err, &target) || target.HttpMsg != "already exists" {
+		t.Fatalf("expected errors.As to find the Error with its HttpMsg in %v", err)
+	}
+	w := httptest.NewRecorder()
+	status, ok := err.ToHTTPResponse(w)
+	if !ok || status != http.StatusConflict {
+		t.Fatalf("expected (%d, true), got (%d, %t)", http.StatusConflict, status, ok)
+	}
+	if w.Body.String() != "already exists\n" {
+		t.Errorf("expected body 'already exists', got '%s'", w.Body.String())
+	}
+}
+
+func TestWrap_preservesInnerClass(t *testing.T) {
+	checker := errors.AllErrCheck{}
+	err := errors.Wrapf(errors.NewNotFound("no user"), "handle %s", "req")
+	if !checker.IsNotFoundError(err) {
+		t.Errorf("Expected IsNotFoundError() true but got %t",
+			checker.IsNotFoundError(err))
+	}
+	if err.Error() != "handle req: no user" {
+		t.Errorf("expected error message '%s', got '%s'", "handle req: no user", err.Error())
+	}
+}